@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/envygeeks/envp/crypt"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type encryptCmd struct {
+	*cobra.Command
+}
+
+var (
+	Encrypt = (&encryptCmd{
+		&cobra.Command{
+			Short: "Encrypt a value for decrypt",
+			Use:   "encrypt [value]",
+
+			Long: tS(`
+				Encrypts a value (or stdin) into ENC[...] so that you
+				can commit it alongside your templates, by default it uses
+				the key in $ENVP_KEY, or $ENVP_KEY_FILE, pass --recipient
+				to encrypt to an x25519 recipient instead.
+			`),
+		},
+	}).Init()
+)
+
+func init() {
+	Root.AddCommand(Encrypt.Command)
+}
+
+// Init finishes initializing encryptCmd
+func (e *encryptCmd) Init() *encryptCmd {
+	e.Run = e.Start
+	e.Args = cobra.MaximumNArgs(1)
+	e.Flags().String("recipient", "", "x25519 recipient to encrypt to")
+	e.Flags().String("keygen", "", "generate a key (secretbox, x25519)")
+	return e
+}
+
+// value pulls the value from args, or stdin
+func (e *encryptCmd) value(args []string) []byte {
+	if len(args) == 1 {
+		return []byte(args[0])
+	}
+
+	b, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return []byte(strings.TrimSuffix(string(b), "\n"))
+}
+
+// keygen prints a new key, or pair of keys
+func (e *encryptCmd) keygen(kind string) {
+	switch kind {
+	case crypt.Secretbox:
+		key, err := crypt.GenerateKey()
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		fmt.Println(crypt.EncodeKey(key))
+	case crypt.X25519:
+		recipient, identity, err := crypt.GenerateIdentity()
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		fmt.Printf("Identity: %s\n", crypt.EncodeKey(identity))
+		fmt.Printf("Recipient: %s\n", crypt.EncodeKey(recipient))
+	default:
+		logrus.Fatalf("unknown key type %q", kind)
+	}
+}
+
+// seal encrypts to the recipient, or the env key
+func (e *encryptCmd) seal(b []byte, recipient string) string {
+	if recipient != "" {
+		key, err := crypt.DecodeKey(recipient)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		out, err := crypt.SealTo(b, key)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		return out
+	}
+
+	keys, err := crypt.LoadKeys()
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if keys.Key == nil {
+		logrus.Fatalf("set $%s, $%s, or --recipient",
+			crypt.KeyEnv, crypt.KeyFileEnv)
+	}
+
+	out, err := crypt.Seal(b, keys.Key)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return out
+}
+
+// Start runs the command
+func (e *encryptCmd) Start(_ *cobra.Command, args []string) {
+	kind, err := e.Flags().GetString("keygen")
+	if err != nil {
+		logrus.Fatalln(err)
+	} else {
+		if kind != "" {
+			e.keygen(kind)
+			return
+		}
+	}
+
+	recipient, err := e.Flags().GetString("recipient")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	fmt.Println(e.seal(e.value(args), recipient))
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"io/ioutil"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/envygeeks/envp/crypt"
 	upstream "github.com/envygeeks/envp/template"
+	"github.com/envygeeks/envp/template/helpers"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -54,8 +58,19 @@ func (r *rootCmd) Init() *rootCmd {
 	r.PreRun = r.PreStart
 	r.Flags().String("write-to", "", "write to (stdout)")
 	r.PersistentFlags().Bool("debug", false, "verbose debug output")
+	r.PersistentFlags().String("state-file", "", "where to persist secrets ($ENVP_STATE_FILE)")
 	r.Flags().StringArray("file", []string{}, "files to read in as templates")
+	r.Flags().StringArray("data", []string{}, "json, or yaml files for .Data")
 	r.Flags().Bool("version", false, "the current app version")
+	r.Flags().String("consul-addr", "", "consul address ($CONSUL_HTTP_ADDR)")
+	r.Flags().String("etcd-addr", "", "etcd address ($ETCD_ADDR)")
+	r.Flags().String("escape", "", "auto-escape for json, yaml, toml, ini, xml, html, shell, or none")
+	r.Flags().String("validate", "", "validate as json, yaml, toml, xml, ini, or none")
+	r.Flags().String("schema", "", "a JSON Schema to validate the output against")
+	r.Flags().StringArray("allow-read", []string{}, "dirs that templates can read files from")
+	r.Flags().StringArray("allow-write", []string{}, "dirs that templates can write files to")
+	r.Flags().String("now", "", "the time for now, RFC3339, or @unix ($SOURCE_DATE_EPOCH)")
+	r.Flags().Duration("dns-timeout", 5*time.Second, "how long a dns lookup can take")
 	r.Run = r.Start
 	return r
 }
@@ -70,6 +85,26 @@ func (r *rootCmd) files() []string {
 	return files
 }
 
+// allowRead pulls down allow-read
+func (r *rootCmd) allowRead() []string {
+	allowRead, err := r.Flags().GetStringArray("allow-read")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return allowRead
+}
+
+// allowWrite pulls down allow-write
+func (r *rootCmd) allowWrite() []string {
+	allowWrite, err := r.Flags().GetStringArray("allow-write")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return allowWrite
+}
+
 // writeTo pulls down write-to
 func (r *rootCmd) writeTo() string {
 	writeTo, err := r.Flags().GetString("write-to")
@@ -80,6 +115,152 @@ func (r *rootCmd) writeTo() string {
 	return writeTo
 }
 
+// escape pulls down escape
+func (r *rootCmd) escape() string {
+	escape, err := r.Flags().GetString("escape")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return escape
+}
+
+// validate pulls down validate, it's off unless
+// you ask for it, and "none" is off too.
+func (r *rootCmd) validate() string {
+	validate, err := r.Flags().GetString("validate")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if validate == "none" {
+		return ""
+	}
+
+	return validate
+}
+
+// schema pulls down schema, and reads it
+func (r *rootCmd) schema() []byte {
+	schema, err := r.Flags().GetString("schema")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if schema == "" {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(schema)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return b
+}
+
+// check validates the output, before it's written
+func (r *rootCmd) check(b []byte) {
+	format, schema := r.validate(), r.schema()
+	if schema != nil && (format == "" || format == "xml") {
+		logrus.Fatalln("--schema needs --validate json, yaml, toml, or ini")
+	}
+
+	if format == "" {
+		return
+	}
+
+	data, err := upstream.Validate(b, format)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if schema != nil {
+		if err := upstream.ValidateSchema(data, schema); err != nil {
+			logrus.Fatalln(err)
+		}
+	}
+}
+
+// now pulls down now, and parses it
+func (r *rootCmd) now() time.Time {
+	now, err := r.Flags().GetString("now")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if now == "" {
+		return time.Time{}
+	}
+
+	t, err := helpers.ParseTime(now)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return t
+}
+
+// dnsTimeout pulls down dns-timeout
+func (r *rootCmd) dnsTimeout() time.Duration {
+	dnsTimeout, err := r.Flags().GetDuration("dns-timeout")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return dnsTimeout
+}
+
+// stateFile pulls down state-file
+func (r *rootCmd) stateFile() string {
+	stateFile, err := r.PersistentFlags().GetString("state-file")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return stateFile
+}
+
+// data pulls down data
+func (r *rootCmd) data() []string {
+	data, err := r.Flags().GetStringArray("data")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return data
+}
+
+// keys loads the keys for --data, it's only done
+// if there's data, so a bad key doesn't stop you.
+func (r *rootCmd) keys(data []string) *crypt.Keys {
+	if len(data) == 0 {
+		return nil
+	}
+
+	keys, err := crypt.LoadKeys()
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return keys
+}
+
+// kvAddrs pulls down consul-addr, and etcd-addr
+func (r *rootCmd) kvAddrs() (string, string) {
+	consul, err := r.Flags().GetString("consul-addr")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	etcd, err := r.Flags().GetString("etcd-addr")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return consul, etcd
+}
+
 // preStart runs stuff before start
 func (r *rootCmd) PreStart(*cobra.Command, []string) {
 	logrus.SetLevel(logrus.WarnLevel)
@@ -100,14 +281,29 @@ func (r *rootCmd) Start(*cobra.Command, []string) {
 	}
 
 	template := upstream.New()
+	template.Helpers.ConsulAddr, template.Helpers.EtcdAddr = r.kvAddrs()
+	template.Helpers.StateFile = r.stateFile()
+	template.Helpers.Time = r.now()
+	template.Helpers.Version = version
+	template.Helpers.AllowRead = r.allowRead()
+	template.Helpers.AllowWrite = r.allowWrite()
+	template.Helpers.DNSTimeout = r.dnsTimeout()
+	template.Escape(r.escape())
 	writeTo, files := r.writeTo(), r.files()
-	readers, writer := upstream.Open(files, writeTo)
-	defer upstream.Close(readers, writer)
+	readers := upstream.OpenReaders(files)
 	template.ParseFiles(readers)
 	if len(readers) == 1 {
 		template.Use(readers[0])
 	}
 
+	data := r.data()
+	template.ReadData(data, r.keys(data))
+
+	// Validate before the writer opens the
+	// file, so a bad render doesn't clobber it.
 	byte := template.Compile()
+	r.check(byte)
+	writer := upstream.OpenWriter(writeTo)
+	defer upstream.Close(readers, writer)
 	template.Write(byte, writer)
 }
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/envygeeks/envp/state"
+	"github.com/envygeeks/envp/template/helpers"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type secretsCmd struct {
+	*cobra.Command
+}
+
+var (
+	Secrets = (&secretsCmd{
+		&cobra.Command{
+			Short: "Manage persisted secrets",
+			Use:   "secrets",
+		},
+	}).Init()
+)
+
+func init() {
+	Root.AddCommand(Secrets.Command)
+}
+
+// Init finishes initializing secretsCmd
+func (s *secretsCmd) Init() *secretsCmd {
+	s.AddCommand(&cobra.Command{
+		Short: "List persisted secrets",
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Run:   s.List,
+	})
+
+	s.AddCommand(&cobra.Command{
+		Short: "Rotate persisted secrets",
+		Long: tS(`
+			Regenerates the passwords you name, anything else (like
+			keys, or certificates) is removed, and will be generated
+			again the next time that you render a template.
+		`),
+
+		Use:  "rotate [name...]",
+		Args: cobra.MinimumNArgs(1),
+		Run:  s.Rotate,
+	})
+
+	return s
+}
+
+// open opens the state from --state-file
+func (s *secretsCmd) open() *state.State {
+	st, err := state.Open(Root.stateFile())
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return st
+}
+
+// List prints the names of the secrets
+func (s *secretsCmd) List(*cobra.Command, []string) {
+	st := s.open()
+	defer st.Close()
+
+	for _, name := range st.Names() {
+		secret := st.Get(name)
+		fmt.Printf("%s\t%s\t%s\n", name, secret.Kind,
+			secret.Created.Format("2006-01-02"))
+	}
+}
+
+// Rotate regenerates, or removes the secrets
+func (s *secretsCmd) Rotate(_ *cobra.Command, args []string) {
+	st := s.open()
+	defer st.Close()
+
+	for _, name := range args {
+		secret := st.Get(name)
+		if secret == nil {
+			logrus.Fatalf("unable to find %s", name)
+		}
+
+		if secret.Kind == helpers.PasswordKind {
+			st.Set(name, &state.Secret{
+				Value: helpers.Password(secret.Size),
+				Kind:  secret.Kind,
+				Size:  secret.Size,
+			})
+
+			continue
+		}
+
+		st.Delete(name)
+	}
+
+	if err := st.Save(); err != nil {
+		logrus.Fatalln(err)
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const (
+	// Secretbox is a symmetric key shared by
+	// whoever encrypts, and whoever decrypts.
+	Secretbox = "secretbox"
+
+	// X25519 is an age-style recipient key, you
+	// encrypt to the public half, and decrypt with
+	// the private half (the identity.)
+	X25519 = "x25519"
+
+	// KeyEnv, and friends are where we look
+	// for keys when you don't hand them to us.
+	KeyEnv          = "ENVP_KEY"
+	KeyFileEnv      = "ENVP_KEY_FILE"
+	IdentityEnv     = "ENVP_IDENTITY"
+	IdentityFileEnv = "ENVP_IDENTITY_FILE"
+
+	prefix   = "ENC["
+	suffix   = "]"
+	keySize  = 32
+	nonceLen = 24
+)
+
+var (
+	// ErrNoKey is returned when we need a key
+	// to decrypt something, but we have none.
+	ErrNoKey = errors.New("no key to decrypt with")
+
+	// ErrMalformed is returned when the value
+	// looks like ENC[...] but isn't one of ours.
+	ErrMalformed = errors.New("malformed encrypted value")
+)
+
+// Key is a 32 byte secretbox key, an x25519
+// public key (recipient) or private key (identity.)
+type Key = [keySize]byte
+
+// Keys holds what we can decrypt with
+type Keys struct {
+	Key      *Key
+	Identity *Key
+}
+
+// EncodeKey encodes a key so you can store it
+func EncodeKey(k *Key) string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// DecodeKey decodes a key that was encoded
+func DecodeKey(s string) (*Key, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes", keySize)
+	}
+
+	k := new(Key)
+	copy(k[:], b)
+	return k, nil
+}
+
+// GenerateKey generates a secretbox key
+func GenerateKey() (*Key, error) {
+	k := new(Key)
+	if _, err := io.ReadFull(rand.Reader, k[:]); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// GenerateIdentity generates an x25519 pair, the
+// recipient is public, and the identity is private.
+func GenerateIdentity() (recipient, identity *Key, err error) {
+	return box.GenerateKey(rand.Reader)
+}
+
+// envKey pulls a key from env, or a file named in env
+func envKey(env, fileEnv string) (*Key, error) {
+	if v := os.Getenv(env); v != "" {
+		return DecodeKey(v)
+	}
+
+	if f := os.Getenv(fileEnv); f != "" {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		return DecodeKey(string(b))
+	}
+
+	return nil, nil
+}
+
+// LoadKeys loads whatever keys exist in the env
+// either directly, or through a file, it's fine for
+// none of them to exist, until you need to decrypt.
+func LoadKeys() (*Keys, error) {
+	key, err := envKey(KeyEnv, KeyFileEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := envKey(IdentityEnv, IdentityFileEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keys{
+		Identity: identity,
+		Key:      key,
+	}, nil
+}
+
+func nonce() (*[nonceLen]byte, error) {
+	n := new([nonceLen]byte)
+	if _, err := io.ReadFull(rand.Reader, n[:]); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+func wrap(kind string, b []byte) string {
+	return prefix + kind + "," + base64.StdEncoding.EncodeToString(b) + suffix
+}
+
+// Seal encrypts with a secretbox key
+func Seal(b []byte, k *Key) (string, error) {
+	n, err := nonce()
+	if err != nil {
+		return "", err
+	}
+
+	out := secretbox.Seal(n[:], b, n, k)
+	return wrap(Secretbox, out), nil
+}
+
+// SealTo encrypts to an x25519 recipient, each value
+// gets it's own ephemeral key, so only the identity
+// that owns the recipient can ever open it.
+func SealTo(b []byte, recipient *Key) (string, error) {
+	epub, epriv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	n, err := nonce()
+	if err != nil {
+		return "", err
+	}
+
+	out := append(epub[:], n[:]...)
+	out = box.Seal(out, b, n, recipient, epriv)
+	return wrap(X25519, out), nil
+}
+
+// IsEncrypted tells you if a value is ENC[...]
+func IsEncrypted(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, prefix) &&
+		strings.HasSuffix(s, suffix)
+}
+
+func unwrap(s string) (string, []byte, error) {
+	if !IsEncrypted(s) {
+		return "", nil, ErrMalformed
+	}
+
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, prefix), suffix)
+	parts := strings.SplitN(s, ",", 2)
+	if len(parts) != 2 {
+		return "", nil, ErrMalformed
+	}
+
+	b, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, ErrMalformed
+	}
+
+	return parts[0], b, nil
+}
+
+// Open decrypts an ENC[...] value with your keys
+func Open(s string, keys *Keys) ([]byte, error) {
+	kind, b, err := unwrap(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out []byte
+		ok  bool
+	)
+
+	switch kind {
+	case Secretbox:
+		if keys == nil || keys.Key == nil {
+			return nil, ErrNoKey
+		}
+
+		if len(b) < nonceLen {
+			return nil, ErrMalformed
+		}
+
+		n := new([nonceLen]byte)
+		copy(n[:], b[:nonceLen])
+		out, ok = secretbox.Open(nil, b[nonceLen:], n, keys.Key)
+	case X25519:
+		if keys == nil || keys.Identity == nil {
+			return nil, ErrNoKey
+		}
+
+		if len(b) < keySize+nonceLen {
+			return nil, ErrMalformed
+		}
+
+		epub, n := new(Key), new([nonceLen]byte)
+		copy(epub[:], b[:keySize])
+		copy(n[:], b[keySize:keySize+nonceLen])
+		out, ok = box.Open(nil, b[keySize+nonceLen:], n, epub, keys.Identity)
+	default:
+		return nil, fmt.Errorf("unknown encryption %q", kind)
+	}
+
+	if !ok {
+		return nil, errors.New("unable to decrypt, wrong key?")
+	}
+
+	return out, nil
+}
+
+// OpenAll walks maps, and slices (like the ones you
+// get from decoding a data file, YAML's included) and
+// decrypts every ENC[...] string it finds in place.
+func OpenAll(v interface{}, keys *Keys) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		if IsEncrypted(t) {
+			b, err := Open(t, keys)
+			if err != nil {
+				return nil, err
+			}
+
+			return string(b), nil
+		}
+	case map[string]interface{}:
+		for k, vv := range t {
+			out, err := OpenAll(vv, keys)
+			if err != nil {
+				return nil, err
+			}
+
+			t[k] = out
+		}
+	case map[interface{}]interface{}:
+		for k, vv := range t {
+			out, err := OpenAll(vv, keys)
+			if err != nil {
+				return nil, err
+			}
+
+			t[k] = out
+		}
+	case []interface{}:
+		for i, vv := range t {
+			out, err := OpenAll(vv, keys)
+			if err != nil {
+				return nil, err
+			}
+
+			t[i] = out
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeal(t *testing.T) {
+	key, _ := GenerateKey()
+	other, _ := GenerateKey()
+	out, err := Seal([]byte("hello"), key)
+	if assert.Nil(t, err) {
+		assert.True(t, IsEncrypted(out))
+		actual, err := Open(out, &Keys{Key: key})
+		assert.Nil(t, err)
+		assert.Equal(t, "hello", string(actual))
+
+		_, err = Open(out, &Keys{Key: other})
+		assert.NotNil(t, err)
+		_, err = Open(out, &Keys{})
+		assert.Equal(t, ErrNoKey, err)
+	}
+}
+
+func TestSealTo(t *testing.T) {
+	recipient, identity, _ := GenerateIdentity()
+	_, other, _ := GenerateIdentity()
+	out, err := SealTo([]byte("hello"), recipient)
+	if assert.Nil(t, err) {
+		actual, err := Open(out, &Keys{Identity: identity})
+		assert.Nil(t, err)
+		assert.Equal(t, "hello", string(actual))
+
+		_, err = Open(out, &Keys{Identity: other})
+		assert.NotNil(t, err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	type TestStruct struct {
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			description: "it errors on plain values",
+			input:       "hello",
+		},
+		TestStruct{
+			description: "it errors on bad base64",
+			input:       "ENC[secretbox,!!]",
+		},
+		TestStruct{
+			description: "it errors on unknown kinds",
+			input:       "ENC[rot13,aGVsbG8=]",
+		},
+		TestStruct{
+			description: "it errors on short values",
+			input:       "ENC[secretbox,aGVsbG8=]",
+		},
+	} {
+		key, _ := GenerateKey()
+		_, err := Open(test.input, &Keys{Key: key})
+		assert.NotNil(t, err, test.description)
+	}
+}
+
+func TestOpenAll(t *testing.T) {
+	key, _ := GenerateKey()
+	enc, _ := Seal([]byte("secret"), key)
+	data := map[string]interface{}{
+		"plain": "value",
+		"list":  []interface{}{enc, 1},
+		"deep": map[string]interface{}{
+			"password": enc,
+		},
+		"yaml": map[interface{}]interface{}{
+			"password": enc,
+		},
+	}
+
+	_, err := OpenAll(data, &Keys{Key: key})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "value", data["plain"])
+		assert.Equal(t, "secret", data["list"].([]interface{})[0])
+		assert.Equal(t, "secret", data["deep"].(map[string]interface{})["password"])
+		assert.Equal(t, "secret", data["yaml"].(map[interface{}]interface{})["password"])
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	key, _ := GenerateKey()
+	_, identity, _ := GenerateIdentity()
+	file, _ := ioutil.TempFile("", "test-load-keys")
+	defer func() { file.Close(); os.Remove(file.Name()) }()
+	file.WriteString(EncodeKey(identity) + "\n")
+
+	os.Setenv(KeyEnv, EncodeKey(key))
+	os.Setenv(IdentityFileEnv, file.Name())
+	defer os.Unsetenv(IdentityFileEnv)
+	defer os.Unsetenv(KeyEnv)
+
+	keys, err := LoadKeys()
+	if assert.Nil(t, err) {
+		assert.Equal(t, key, keys.Key)
+		assert.Equal(t, identity, keys.Identity)
+	}
+}
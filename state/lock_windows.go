@@ -0,0 +1,17 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package state
+
+import (
+	"os"
+)
+
+// Windows doesn't have flock, and envp is really
+// meant for containers, so we don't lock there.
+func lockFile(f *os.File) error   { return nil }
+func unlockFile(f *os.File) error { return nil }
@@ -0,0 +1,203 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/envygeeks/envp/crypt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// PathEnv is where we look for the state
+	// file when you don't explicitly give us one.
+	PathEnv = "ENVP_STATE_FILE"
+
+	// DefaultPath is used when nothing else is
+	// set, mount it as a volume to persist it.
+	DefaultPath = "/var/lib/envp/state.json"
+
+	fileMode = 0600
+	dirMode  = 0700
+)
+
+// Secret is a generated value that we hold on
+// to so that renders after the first get the same.
+type Secret struct {
+	Kind    string    `json:"kind"`
+	Value   string    `json:"value"`
+	Size    uint      `json:"size,omitempty"`
+	Params  string    `json:"params,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// State is the on-disk store of secrets, it's
+// locked while open, so only one envp writes it.
+type State struct {
+	Secrets map[string]*Secret `json:"secrets"`
+
+	path string
+	keys *crypt.Keys
+	lock *os.File
+}
+
+// Path resolves the path to the state file, it
+// prefers what you give, then the env, then default.
+func Path(p string) string {
+	if p == "" {
+		p = os.Getenv(PathEnv)
+		if p == "" {
+			p = DefaultPath
+		}
+	}
+
+	return p
+}
+
+// Open locks, and reads the state file, if you have
+// a key in your env the file is encrypted with it.
+// Make sure you Close() so the lock is released.
+func Open(p string) (*State, error) {
+	p = Path(p)
+	keys, err := crypt.LoadKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), dirMode); err != nil {
+		return nil, err
+	}
+
+	logrus.Debugf("locking state %s", p)
+	lock, err := os.OpenFile(p+".lock", os.O_CREATE|os.O_RDWR, fileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(lock); err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	s := &State{
+		Secrets: map[string]*Secret{},
+		lock:    lock,
+		keys:    keys,
+		path:    p,
+	}
+
+	if err := s.read(); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// read reads, and decrypts the file if it exists
+func (s *State) read() error {
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if crypt.IsEncrypted(string(b)) {
+		b, err = crypt.Open(string(b), s.keys)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(b, s)
+}
+
+// Save writes the state to a temporary file, and
+// then moves it into place so it's never half-written.
+func (s *State) Save() error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if s.keys != nil && s.keys.Key != nil {
+		out, err := crypt.Seal(b, s.keys.Key)
+		if err != nil {
+			return err
+		}
+
+		b = []byte(out)
+	}
+
+	tmp := s.path + ".tmp"
+	logrus.Debugf("writing state %s", s.path)
+	if err := ioutil.WriteFile(tmp, b, fileMode); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp, fileMode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+// Close releases the lock on the state file
+func (s *State) Close() error {
+	if s.lock == nil {
+		return nil
+	}
+
+	defer func() { s.lock = nil }()
+	if err := unlockFile(s.lock); err != nil {
+		s.lock.Close()
+		return err
+	}
+
+	return s.lock.Close()
+}
+
+// Get gets a secret by name, it's nil if unknown
+func (s *State) Get(name string) *Secret {
+	return s.Secrets[name]
+}
+
+// Set sets a secret by name, you still need to Save
+func (s *State) Set(name string, secret *Secret) {
+	if secret.Created.IsZero() {
+		secret.Created = time.Now().UTC()
+	}
+
+	s.Secrets[name] = secret
+}
+
+// Delete removes a secret, you still need to Save
+func (s *State) Delete(name string) {
+	delete(s.Secrets, name)
+}
+
+// Names lists the names of the secrets, sorted
+func (s *State) Names() []string {
+	var names []string
+	for k := range s.Secrets {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+	return names
+}
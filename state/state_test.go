@@ -0,0 +1,91 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/envygeeks/envp/crypt"
+	"github.com/stretchr/testify/assert"
+)
+
+func tempState(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "test-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Join(dir, "state.json"), func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/a", Path("/a"))
+	assert.Equal(t, DefaultPath, Path(""))
+	os.Setenv(PathEnv, "/b")
+	defer os.Unsetenv(PathEnv)
+	assert.Equal(t, "/b", Path(""))
+}
+
+func TestSave(t *testing.T) {
+	path, cleanup := tempState(t)
+	defer cleanup()
+
+	s, err := Open(path)
+	if assert.Nil(t, err) {
+		s.Set("hello", &Secret{Value: "world", Kind: "password"})
+		assert.Nil(t, s.Save())
+		assert.Nil(t, s.Close())
+	}
+
+	finfo, err := os.Stat(path)
+	if assert.Nil(t, err) {
+		assert.Equal(t, os.FileMode(0600), finfo.Mode().Perm())
+	}
+
+	s, err = Open(path)
+	if assert.Nil(t, err) {
+		defer s.Close()
+		if assert.NotNil(t, s.Get("hello")) {
+			assert.Equal(t, "world", s.Get("hello").Value)
+			assert.False(t, s.Get("hello").Created.IsZero())
+		}
+
+		assert.Equal(t, []string{"hello"}, s.Names())
+		s.Delete("hello")
+		assert.Nil(t, s.Get("hello"))
+	}
+}
+
+func TestSave__encrypted(t *testing.T) {
+	path, cleanup := tempState(t)
+	defer cleanup()
+
+	key, _ := crypt.GenerateKey()
+	os.Setenv(crypt.KeyEnv, crypt.EncodeKey(key))
+	defer os.Unsetenv(crypt.KeyEnv)
+
+	s, err := Open(path)
+	if assert.Nil(t, err) {
+		s.Set("hello", &Secret{Value: "world"})
+		assert.Nil(t, s.Save())
+		s.Close()
+	}
+
+	b, _ := ioutil.ReadFile(path)
+	assert.True(t, crypt.IsEncrypted(string(b)))
+	assert.False(t, strings.Contains(string(b), "world"))
+
+	s, err = Open(path)
+	if assert.Nil(t, err) {
+		defer s.Close()
+		assert.Equal(t, "world", s.Get("hello").Value)
+	}
+}
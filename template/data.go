@@ -0,0 +1,56 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/envygeeks/envp/crypt"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// decode decodes a data file by it's extension
+func decode(file string, b []byte) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return out, json.Unmarshal(b, &out)
+	case ".yml", ".yaml":
+		return out, yaml.Unmarshal(b, &out)
+	}
+
+	logrus.Fatalf("%s isn't json, or yaml", file)
+	return nil, nil
+}
+
+// ReadData reads JSON, or YAML data files into .Data,
+// the keys of later files win, and ENC[...] values are
+// decrypted with the keys that you hand to us.
+func (t *Template) ReadData(files []string, keys *crypt.Keys) {
+	for _, file := range files {
+		logrus.Debugf("reading data from %s", file)
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		data, err := decode(file, b)
+		if err != nil {
+			logrus.Fatalf("%s: %s", file, err)
+		}
+
+		if _, err := crypt.OpenAll(data, keys); err != nil {
+			logrus.Fatalf("%s: %s", file, err)
+		}
+
+		for k, v := range data {
+			t.values[k] = v
+		}
+	}
+}
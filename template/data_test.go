@@ -0,0 +1,44 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/envygeeks/envp/crypt"
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+ */
+func TestReadData(t *testing.T) {
+	key, err := crypt.GenerateKey()
+	assert.Nil(t, err)
+	secret, err := crypt.Seal([]byte("hunter2"), key)
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "envp")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	yml, json := filepath.Join(dir, "a.yml"), filepath.Join(dir, "b.json")
+	ioutil.WriteFile(yml, []byte("name: a\ndb:\n  password: "+secret), 0600)
+	ioutil.WriteFile(json, []byte(`{"name": "b"}`), 0600)
+
+	template := New()
+	template.ReadData([]string{yml, json}, &crypt.Keys{Key: key})
+	template.ParseFile(&TestReader{
+		Reader: strings.NewReader("{{ .Data.name }} {{ .Data.db.password }}"),
+		_name:  "data",
+	})
+
+	actual := string(template.Compile())
+	assert.Equal(t, "b hunter2", actual,
+		"it decrypts, and later files win")
+}
@@ -0,0 +1,192 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"reflect"
+	upstream "text/template"
+	"text/template/parse"
+
+	"github.com/envygeeks/envp/template/helpers"
+	"github.com/sirupsen/logrus"
+)
+
+// noEscape turns auto-escaping off, even for a
+// file that's named after a format (app.json.gohtml)
+const noEscape = "none"
+
+// Escape turns on auto-escaping for a format (json,
+// yaml, toml, ini, xml, html, or shell) for every file,
+// without it we detect it from the name (app.json.gohtml)
+// and "none" turns it off, even if there's one.
+func (t *Template) Escape(format string) {
+	if format != "" && format != "html" && format != noEscape && helpers.EscaperFor(format) == "" {
+		logrus.Fatalf("unable to escape %s", format)
+	}
+
+	t.escape = format
+}
+
+// format is the format for a template, it's what
+// you asked for, or what's detected from the names, a
+// define falls back to it's file, and then to the main.
+func (t *Template) format(names ...string) string {
+	if t.escape == noEscape {
+		return ""
+	} else if t.escape != "" {
+		return t.escape
+	}
+
+	for _, name := range names {
+		if format := helpers.FormatOf(name); format != "" {
+			return format
+		}
+	}
+
+	return ""
+}
+
+// escapeTrees adds an escaper to every action that
+// outputs something, much like html/template does, so
+// you don't need to remember to quote every value, it
+// can be run again, to get the templates tpl parses.
+func (t *Template) escapeTrees(main string) {
+	t.main = main
+	for _, v := range t.Templates() {
+		if v.Tree == nil || v.Tree.Root == nil || t.escaped[v.Tree] {
+			continue
+		}
+
+		t.escaped[v.Tree] = true
+		format := t.format(v.Name(), v.Tree.ParseName, main)
+		if escaper := helpers.EscaperFor(format); escaper != "" {
+			logrus.Debugf("escaping %s as %s", v.Name(), format)
+			escapeList(v.Tree.Root, format, escaper)
+		}
+	}
+}
+
+// escapeList walks a list, and adds the escaper
+func escapeList(list *parse.ListNode, format, escaper string) {
+	if list == nil {
+		return
+	}
+
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			escapeAction(n, format, escaper)
+		case *parse.IfNode:
+			escapeList(n.List, format, escaper)
+			escapeList(n.ElseList, format, escaper)
+		case *parse.RangeNode:
+			escapeList(n.List, format, escaper)
+			escapeList(n.ElseList, format, escaper)
+		case *parse.WithNode:
+			escapeList(n.List, format, escaper)
+			escapeList(n.ElseList, format, escaper)
+		}
+	}
+}
+
+// escapeAction adds the escaper to an action, unless
+// it's an assignment (no output) or it's already safe.
+func escapeAction(n *parse.ActionNode, format, escaper string) {
+	pipe := n.Pipe
+	if pipe == nil || len(pipe.Decl) > 0 || len(pipe.Cmds) == 0 {
+		return
+	}
+
+	last := pipe.Cmds[len(pipe.Cmds)-1]
+	if len(last.Args) > 0 {
+		if ident, ok := last.Args[0].(*parse.IdentifierNode); ok {
+			if helpers.IsSafe(format, ident.Ident) {
+				return
+			}
+		}
+	}
+
+	pipe.Cmds = append(pipe.Cmds, &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Args: []parse.Node{
+			parse.NewIdentifier(escaper).SetTree(nil).
+				SetPos(n.Position()),
+		},
+	})
+}
+
+// safeHTML wraps a helper, so that what it gives
+// back is marked as HTML, and isn't escaped again.
+func safeHTML(fn interface{}) interface{} {
+	v := reflect.ValueOf(fn)
+	typ := v.Type()
+	in := make([]reflect.Type, typ.NumIn())
+	for i := range in {
+		in[i] = typ.In(i)
+	}
+
+	out := []reflect.Type{reflect.TypeOf(htmltemplate.HTML(""))}
+	wrapped := reflect.FuncOf(in, out, typ.IsVariadic())
+	return reflect.MakeFunc(wrapped, func(args []reflect.Value) []reflect.Value {
+		var res []reflect.Value
+		if typ.IsVariadic() {
+			res = v.CallSlice(args)
+		} else {
+			res = v.Call(args)
+		}
+
+		return []reflect.Value{
+			reflect.ValueOf(htmltemplate.HTML(res[0].String())),
+		}
+	}).Interface()
+}
+
+// compileHTML executes the template with html/template
+// so that everything is escaped in context, the parsed
+// trees are shared, so parse everything before this.
+func (t *Template) compileHTML(template *upstream.Template) []byte {
+	var html *htmltemplate.Template
+
+	// Partials are run by html/template, so they're
+	// escaped in context too, and what they give back
+	// isn't escaped again, tpl's templates can't be
+	// added after we run, so they're escaped as text.
+	t.Helpers.Parsed = nil
+	t.Helpers.Render = func(name string, data interface{}) (string, error) {
+		buf := &bytes.Buffer{}
+		err := html.ExecuteTemplate(buf, name, data)
+		return buf.String(), err
+	}
+
+	funcs := t.Helpers.FuncMap()
+	funcs["raw"] = func(s string) htmltemplate.HTML {
+		return htmltemplate.HTML(s)
+	}
+
+	for _, name := range helpers.Partials {
+		funcs[name] = safeHTML(funcs[name])
+	}
+
+	html = htmltemplate.New(template.Name()).Funcs(funcs)
+	for _, v := range t.Templates() {
+		if v.Tree == nil {
+			continue
+		}
+
+		if _, err := html.AddParseTree(v.Name(), v.Tree); err != nil {
+			logrus.Fatalln(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	logrus.Debugf("executing %s as html", template.Name())
+	if err := html.ExecuteTemplate(buf, template.Name(), t.data()); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return buf.Bytes()
+}
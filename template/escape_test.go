@@ -0,0 +1,226 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscape(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		escape      string
+		input       string
+		name        string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    `{"a": "x\"y", "b": 1, "c": {"d":"e"}}`,
+			description: "it escapes json by extension",
+			input:       `{"a": {{ "x\"y" }}, "b": {{ 1 }}, "c": {{ fromJson "{\"d\":\"e\"}" }}}`,
+			name:        "app.json.gohtml",
+		},
+		TestStruct{
+			expected:    `a: "yes"` + "\n" + `b: "x: y"`,
+			description: "it escapes yaml by extension",
+			input:       "a: {{ \"yes\" }}\nb: {{ \"x: y\" }}",
+			name:        "app.yml.gohtml",
+		},
+		TestStruct{
+			expected:    `echo 'it'\''s' $HOME`,
+			description: "it escapes shell when asked",
+			input:       `echo {{ "it's" }} {{ raw "$HOME" }}`,
+			escape:      "shell",
+			name:        "app",
+		},
+		TestStruct{
+			expected:    `<a title="&lt;&amp;&#34;">&lt;b&gt;</a>`,
+			description: "it escapes xml",
+			input:       `<a title="{{ "<&\"" }}">{{ "<b>" }}</a>`,
+			name:        "app.xml.gohtml",
+		},
+		TestStruct{
+			expected:    `{"a": "b"}`,
+			description: "it doesn't escape twice",
+			input:       `{"a": {{ "b" | quoteJson }}}`,
+			name:        "app.json.gohtml",
+		},
+		TestStruct{
+			expected:    `x := "y"`,
+			description: "it doesn't escape assignments",
+			input:       `{{ $x := "y" }}x := {{ $x }}`,
+			escape:      "toml",
+			name:        "app",
+		},
+		TestStruct{
+			expected:    `<a href="/?q=a%26b">&lt;script&gt;</a>`,
+			description: "it uses html/template for html",
+			input:       `<a href="/?q={{ "a&b" }}">{{ "<script>" }}</a>`,
+			name:        "index.html.gohtml",
+		},
+		TestStruct{
+			expected:    "<b>hello</b>",
+			description: "it allows raw html",
+			input:       `{{ raw "<b>hello</b>" }}`,
+			name:        "index.html.gohtml",
+		},
+		TestStruct{
+			expected:    `{"a": "x\"y", "b": 1}`,
+			description: "it doesn't escape partials twice",
+			input:       `{{ define "p" }}{{ .n }}{{ end }}{"a": {{ include "p" (dict "n" "x\"y") }}, "b": {{ tpl "{{ .n }}" (dict "n" 1) }}}`,
+			name:        "app.json.gohtml",
+		},
+		TestStruct{
+			expected:    `{"a": "x", "b": "y"} {"a": "x", "b": "y"}`,
+			description: "tpl can be nested, and repeated",
+			input:       `{{ tpl "{\"a\": {{ .a }}, \"b\": {{ tpl \"{{ . }}\" .b }}}" (dict "a" "x" "b" "y") }} {{ tpl "{\"a\": {{ .a }}, \"b\": {{ .b }}}" (dict "a" "x" "b" "y") }}`,
+			name:        "app.json.gohtml",
+		},
+		TestStruct{
+			expected:    `<p>&lt;b&gt;</p>`,
+			description: "it doesn't escape html partials twice",
+			input:       `{{ define "p" }}<p>{{ . }}</p>{{ end }}{{ include "p" "<b>" }}`,
+			name:        "index.html.gohtml",
+		},
+		TestStruct{
+			expected:    `{{"a"}}`,
+			description: "it's off without a format",
+			input:       `{{ "{{" }}"a"{{ "}}" }}`,
+			name:        "app.gohtml",
+		},
+		TestStruct{
+			expected:    `{"a": "x", "port": 8080}`,
+			description: "it leaves json that quotes itself alone",
+			input:       `{"a": "{{ "x" }}", "port": {{ 8080 }}}`,
+			name:        "app.gohtml",
+		},
+		TestStruct{
+			expected:    "port: 8080\nenabled: true",
+			description: "it leaves yaml alone without a format",
+			input:       "port: {{ 8080 }}\nenabled: {{ true }}",
+			name:        "app.gohtml",
+		},
+		TestStruct{
+			expected:    `{"a": "x"}`,
+			description: "it's off with --escape none",
+			input:       `{"a": "{{ "x" }}"}`,
+			escape:      "none",
+			name:        "app.json.gohtml",
+		},
+	} {
+		template := New()
+		template.Escape(test.escape)
+		reader := &TestReader{
+			Reader: strings.NewReader(test.input),
+			_name:  test.name,
+		}
+
+		template.ParseFile(reader)
+		template.Use(reader)
+		actual := string(template.Compile())
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestEscape__partials(t *testing.T) {
+	for _, name := range []string{
+		"include",
+		"templateString",
+		"strippedTemplate",
+		"indentedTemplate",
+		"fixIndentedTemplate",
+		"templateWithNewline",
+		"indentedTemplateWithNewline",
+	} {
+		call := name + ` "p" .`
+		if strings.HasPrefix(name, "indented") {
+			call = name + ` "p" 0 .`
+		}
+
+		template := New()
+		reader := &TestReader{
+			_name: "base.json.gohtml",
+			Reader: strings.NewReader(`{{ define "p" }}{"a": {{ "x" }}}{{ end }}` +
+				`{"outer": {{ ` + call + ` }}}`),
+		}
+
+		template.ParseFile(reader)
+		template.Use(reader)
+		actual := strings.Replace(string(template.Compile()), "\n", "", -1)
+		assert.Equal(t, `{"outer": {"a": "x"}}`, actual,
+			name+" isn't escaped twice")
+	}
+}
+
+func TestEscape__html(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		escape      string
+		name        string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    `<b>&lt;x&gt;</b>`,
+			description: "it uses the name",
+			name:        "index.html.gohtml",
+		},
+		TestStruct{
+			expected:    `<b><x></b>`,
+			description: "it's off with --escape none",
+			escape:      "none",
+			name:        "index.html.gohtml",
+		},
+		TestStruct{
+			expected:    `<b>&lt;x&gt;</b>`,
+			description: "it uses --escape",
+			escape:      "html",
+			name:        "index.gohtml",
+		},
+		TestStruct{
+			expected:    `<b><x></b>`,
+			description: "it's off without a format",
+			name:        "index.gohtml",
+		},
+	} {
+		template := New()
+		template.Escape(test.escape)
+		reader := &TestReader{
+			Reader: strings.NewReader(`{{ define "p" }}<b>{{ . }}</b>{{ end }}{{ templateString "p" "<x>" }}`),
+			_name:  test.name,
+		}
+
+		template.ParseFile(reader)
+		template.Use(reader)
+
+		actual := string(template.Compile())
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestEscape__defines(t *testing.T) {
+	template := New()
+	for name, input := range map[string]string{
+		"base.gohtml":      `{{ template "part.json.gohtml" }} {{ template "inline" }}`,
+		"part.json.gohtml": `{{ define "inline" }}{{ "c\"d" }}{{ end }}{{ "a\"b" }}`,
+	} {
+		template.ParseFile(&TestReader{
+			Reader: strings.NewReader(input),
+			_name:  name,
+		})
+	}
+
+	actual := string(template.Compile())
+	assert.Equal(t, `"a\"b" "c\"d"`, actual,
+		"it escapes per file")
+}
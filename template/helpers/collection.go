@@ -0,0 +1,256 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// toList turns any slice, or array into a list, so
+// that split, and friends work with the list helpers.
+func toList(v interface{}) []interface{} {
+	if l, ok := v.([]interface{}); ok {
+		return l
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, val.Len())
+		for i := range out {
+			out[i] = val.Index(i).Interface()
+		}
+
+		return out
+	case reflect.Invalid:
+		return []interface{}{}
+	}
+
+	logrus.Fatalf("expected a list, not %T", v)
+	return nil
+}
+
+// toDict makes sure that you gave us a map, a dict
+// is given back as is, so that set, and unset change it.
+func toDict(v interface{}) map[string]interface{} {
+	if d, ok := v.(map[string]interface{}); ok {
+		return d
+	}
+
+	return toMap(v, "dict")
+}
+
+// Dict builds a map from key, value pairs
+func (h *Helpers) Dict(kv ...interface{}) map[string]interface{} {
+	if len(kv)%2 != 0 {
+		logrus.Fatalln("dict needs key, value pairs")
+	}
+
+	out := make(map[string]interface{}, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		out[fmt.Sprint(kv[i])] = kv[i+1]
+	}
+
+	return out
+}
+
+// List builds a list from the values
+func (h *Helpers) List(v ...interface{}) []interface{} {
+	return append([]interface{}{}, v...)
+}
+
+// Append gives you a new list with the values at the end
+func (h *Helpers) Append(l interface{}, v ...interface{}) []interface{} {
+	return append(h.List(toList(l)...), v...)
+}
+
+// Prepend gives you a new list with the value first
+func (h *Helpers) Prepend(l interface{}, v interface{}) []interface{} {
+	return append([]interface{}{v}, toList(l)...)
+}
+
+// Set sets a key on a dict, and gives it back
+func (h *Helpers) Set(d interface{}, k string, v interface{}) map[string]interface{} {
+	dict := toDict(d)
+	dict[k] = v
+	return dict
+}
+
+// Unset removes a key from a dict, and gives it back
+func (h *Helpers) Unset(d interface{}, k string) map[string]interface{} {
+	dict := toDict(d)
+	delete(dict, k)
+	return dict
+}
+
+// HasKey tells you if a dict has the key
+func (h *Helpers) HasKey(d interface{}, k string) bool {
+	_, ok := toDict(d)[k]
+	return ok
+}
+
+// Keys gives you the keys of a dict, sorted
+func (h *Helpers) Keys(d interface{}) []string {
+	return sortedKeys(toDict(d))
+}
+
+// Values gives you the values of a dict, in the
+// order of the keys, so that the output is stable.
+func (h *Helpers) Values(d interface{}) []interface{} {
+	dict := toDict(d)
+	out := make([]interface{}, 0, len(dict))
+	for _, k := range sortedKeys(dict) {
+		out = append(out, dict[k])
+	}
+
+	return out
+}
+
+// Pick gives you a new dict with only the keys
+func (h *Helpers) Pick(d interface{}, keys ...string) map[string]interface{} {
+	dict, out := toDict(d), map[string]interface{}{}
+	for _, k := range keys {
+		if v, ok := dict[k]; ok {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// Omit gives you a new dict without the keys
+func (h *Helpers) Omit(d interface{}, keys ...string) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range toDict(d) {
+		out[k] = v
+	}
+
+	for _, k := range keys {
+		delete(out, k)
+	}
+
+	return out
+}
+
+// SortAlpha sorts a list as strings
+func (h *Helpers) SortAlpha(l interface{}) []string {
+	list := toList(l)
+	out := make([]string, len(list))
+	for i, v := range list {
+		out[i] = fmt.Sprint(v)
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// Uniq gives you a new list without duplicates,
+// the first one of each wins, so order is kept.
+func (h *Helpers) Uniq(l interface{}) []interface{} {
+	out := []interface{}{}
+	for _, v := range toList(l) {
+		found := false
+		for _, vv := range out {
+			if reflect.DeepEqual(v, vv) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// First gives you the first item, or nil
+func (h *Helpers) First(l interface{}) interface{} {
+	if list := toList(l); len(list) > 0 {
+		return list[0]
+	}
+
+	return nil
+}
+
+// Last gives you the last item, or nil
+func (h *Helpers) Last(l interface{}) interface{} {
+	if list := toList(l); len(list) > 0 {
+		return list[len(list)-1]
+	}
+
+	return nil
+}
+
+// Rest gives you everything but the first item
+func (h *Helpers) Rest(l interface{}) []interface{} {
+	if list := toList(l); len(list) > 0 {
+		return h.List(list[1:]...)
+	}
+
+	return []interface{}{}
+}
+
+// Reverse gives you a new list, reversed
+func (h *Helpers) Reverse(l interface{}) []interface{} {
+	list := toList(l)
+	out := make([]interface{}, len(list))
+	for i, v := range list {
+		out[len(list)-1-i] = v
+	}
+
+	return out
+}
+
+// merge deeply merges src (a copy from toMap) into
+// dst, maps are merged into maps, anything else is only
+// set if overwrite is true, or if dst doesn't have it.
+func merge(dst, src map[string]interface{}, overwrite bool) {
+	for k, v := range src {
+		dv, ok := dst[k]
+		if !ok {
+			dst[k] = v
+			continue
+		}
+
+		dm, dok := dv.(map[string]interface{})
+		sm, sok := v.(map[string]interface{})
+		if dok && sok {
+			merge(dm, sm, overwrite)
+			continue
+		}
+
+		if overwrite {
+			dst[k] = v
+		}
+	}
+}
+
+// Merge deeply merges dicts into the first one, the
+// first one to have a key wins, like defaults do.
+func (h *Helpers) Merge(dst interface{}, src ...interface{}) map[string]interface{} {
+	out := toDict(dst)
+	for _, s := range src {
+		merge(out, toMap(s, "merge"), false)
+	}
+
+	return out
+}
+
+// MergeOverwrite deeply merges dicts into the first
+// one, the last one to have a key wins, like overrides.
+func (h *Helpers) MergeOverwrite(dst interface{}, src ...interface{}) map[string]interface{} {
+	out := toDict(dst)
+	for _, s := range src {
+		merge(out, toMap(s, "merge"), true)
+	}
+
+	return out
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollections(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    `{"a":1,"b":"c"}`,
+			description: "dict builds a map",
+			input:       `{{ dict "a" 1 "b" "c" | toJson }}`,
+		},
+		TestStruct{
+			expected:    `[1,"a",["b"]]`,
+			description: "list builds a list",
+			input:       `{{ list 1 "a" (list "b") | toJson }}`,
+		},
+		TestStruct{
+			expected:    `["z","a","b","c"]`,
+			description: "append, and prepend work with split",
+			input:       `{{ prepend (append (split "a,b" ",") "c") "z" | toJson }}`,
+		},
+		TestStruct{
+			expected:    `{"a":2,"c":3}`,
+			description: "set, and unset change the dict",
+			input:       `{{ $d := dict "a" 1 "b" 2 }}{{ $_ := set $d "a" 2 }}{{ $_ := unset $d "b" }}{{ set $d "c" 3 | toJson }}`,
+		},
+		TestStruct{
+			expected:    "true false",
+			description: "hasKey checks keys",
+			input:       `{{ $d := dict "a" 1 }}{{ hasKey $d "a" }} {{ hasKey $d "b" }}`,
+		},
+		TestStruct{
+			expected:    `["a","b","c"] [3,2,1]`,
+			description: "keys, and values are sorted by key",
+			input:       `{{ $d := dict "c" 1 "b" 2 "a" 3 }}{{ keys $d | toJson }} {{ values $d | toJson }}`,
+		},
+		TestStruct{
+			expected:    `{"a":1,"c":3} {"b":2}`,
+			description: "pick, and omit pick, and omit",
+			input:       `{{ $d := dict "a" 1 "b" 2 "c" 3 }}{{ pick $d "a" "c" "x" | toJson }} {{ omit $d "a" "c" | toJson }}`,
+		},
+		TestStruct{
+			expected:    `["1","a","b"]`,
+			description: "sortAlpha sorts as strings",
+			input:       `{{ list "b" 1 "a" | sortAlpha | toJson }}`,
+		},
+		TestStruct{
+			expected:    `["a","b",1]`,
+			description: "uniq keeps the first of each",
+			input:       `{{ list "a" "b" "a" 1 1 | uniq | toJson }}`,
+		},
+		TestStruct{
+			expected:    `a c ["b","c"] ["c","b","a"]`,
+			description: "first, last, rest, and reverse",
+			input:       `{{ $l := list "a" "b" "c" }}{{ first $l }} {{ last $l }} {{ rest $l | toJson }} {{ reverse $l | toJson }}`,
+		},
+		TestStruct{
+			expected:    `<no value> []`,
+			description: "first, and rest work on empty lists",
+			input:       `{{ first list }} {{ rest list | toJson }}`,
+		},
+		TestStruct{
+			expected:    `{"a":{"b":1,"c":3,"d":4},"e":5}`,
+			description: "merge keeps the first one",
+			input:       `{{ merge (dict "a" (dict "b" 1 "c" 3)) (dict "a" (dict "b" 2 "d" 4) "e" 5) | toJson }}`,
+		},
+		TestStruct{
+			expected:    `{"a":{"b":2,"c":3,"d":4},"e":5}`,
+			description: "mergeOverwrite keeps the last one",
+			input:       `{{ mergeOverwrite (dict "a" (dict "b" 1 "c" 3)) (dict "a" (dict "b" 2 "d" 4) "e" 5) | toJson }}`,
+		},
+		TestStruct{
+			expected:    `{"a":{"b":"c","d":"e"}}`,
+			description: "merge works with yaml",
+			input:       `{{ merge (fromYaml "a: {b: c}") (fromJson "{\"a\":{\"d\":\"e\"}}") | toJson }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
+
+func TestMergeLeavesSources(t *testing.T) {
+	helpers := New(template.New("envp"))
+	src := map[string]interface{}{"a": map[string]interface{}{"b": 1}}
+	dst := helpers.Merge(map[string]interface{}{}, src)
+	dst["a"].(map[string]interface{})["b"] = 2
+	assert.Equal(t, 1, src["a"].(map[string]interface{})["b"])
+}
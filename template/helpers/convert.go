@@ -0,0 +1,167 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The to* helpers are lenient, if they can't convert
+// a value you get the zero value, the must* helpers are
+// strict, and return an error, which stops the template.
+
+// ToString converts a value to a string, nil is ""
+func (h *Helpers) ToString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case fmt.Stringer:
+		return t.String()
+	}
+
+	return fmt.Sprint(v)
+}
+
+// ToStrings converts a list into a list of strings
+func (h *Helpers) ToStrings(l interface{}) []string {
+	list := toList(l)
+	out := make([]string, len(list))
+	for i, v := range list {
+		out[i] = h.ToString(v)
+	}
+
+	return out
+}
+
+// MustToInt converts ints, floats (they're truncated),
+// bools, and strings like "10", or "1.5" to an int, strings
+// are base 10 (like the math helpers) so "010" is 10.
+func (h *Helpers) MustToInt(v interface{}) (int, error) {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(val.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(val.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return int(val.Float()), nil
+	case reflect.Bool:
+		if val.Bool() {
+			return 1, nil
+		}
+
+		return 0, nil
+	case reflect.String:
+		s := strings.TrimSpace(val.String())
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return int(i), nil
+		}
+
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return int(f), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unable to convert %#v to an int", v)
+}
+
+// ToInt converts a value to an int, or 0
+func (h *Helpers) ToInt(v interface{}) int {
+	i, _ := h.MustToInt(v)
+	return i
+}
+
+// MustToFloat converts ints, floats, bools, and
+// strings like "1.5", or "10" to a float
+func (h *Helpers) MustToFloat(v interface{}) (float64, error) {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), nil
+	case reflect.Bool:
+		if val.Bool() {
+			return 1, nil
+		}
+
+		return 0, nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val.String()), 64)
+		if err == nil {
+			return f, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unable to convert %#v to a float", v)
+}
+
+// ToFloat converts a value to a float, or 0
+func (h *Helpers) ToFloat(v interface{}) float64 {
+	f, _ := h.MustToFloat(v)
+	return f
+}
+
+// MustToBool converts bools, numbers (0 is false),
+// and strings like true/false, 1/0, yes/no, and on/off
+func (h *Helpers) MustToBool(v interface{}) (bool, error) {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Bool:
+		return val.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return val.Uint() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		return val.Float() != 0, nil
+	case reflect.String:
+		switch strings.ToLower(strings.TrimSpace(val.String())) {
+		case "yes", "y", "on":
+			return true, nil
+		case "no", "n", "off":
+			return false, nil
+		}
+
+		if b, err := strconv.ParseBool(strings.TrimSpace(val.String())); err == nil {
+			return b, nil
+		}
+	}
+
+	return false, fmt.Errorf("unable to convert %#v to a bool", v)
+}
+
+// ToBool converts a value to a bool, or false
+func (h *Helpers) ToBool(v interface{}) bool {
+	b, _ := h.MustToBool(v)
+	return b
+}
+
+// KindOf gives you the kind of a value, like map,
+// slice, string, or int, nil is "invalid"
+func (h *Helpers) KindOf(v interface{}) string {
+	return reflect.ValueOf(v).Kind().String()
+}
+
+// TypeOf gives you the Go type of a value, like
+// map[string]interface {}, or []string, nil is "<nil>"
+func (h *Helpers) TypeOf(v interface{}) string {
+	return fmt.Sprintf("%T", v)
+}
+
+// KindIs tells you if a value is of the kind
+func (h *Helpers) KindIs(kind string, v interface{}) bool {
+	return h.KindOf(v) == kind
+}
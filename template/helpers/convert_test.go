@@ -0,0 +1,96 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "1 [] a 2.5",
+			description: "toString converts anything",
+			input:       `{{ toString 1 }} [{{ toString nil }}] {{ toString "a" }} {{ toString 2.5 }}`,
+		},
+		TestStruct{
+			expected:    `["a","1","true"]`,
+			description: "toStrings converts lists",
+			input:       `{{ list "a" 1 true | toStrings | toJson }}`,
+		},
+		TestStruct{
+			expected:    "10 0 1 1 0 0",
+			description: "toInt converts, or gives you 0",
+			input:       `{{ toInt "10" }} {{ toInt "0x1f" }} {{ toInt "1.9" }} {{ toInt true }} {{ toInt "nope" }} {{ toInt nil }}`,
+		},
+		TestStruct{
+			expected:    "10 10 10",
+			description: "toInt is base 10, like add, so zero padding is fine",
+			input:       `{{ toInt "010" }} {{ mustToInt "010" }} {{ add "010" 0 }}`,
+		},
+		TestStruct{
+			expected:    "1.5 10 0",
+			description: "toFloat converts, or gives you 0",
+			input:       `{{ toFloat "1.5" }} {{ toFloat 10 }} {{ toFloat "nope" }}`,
+		},
+		TestStruct{
+			expected:    "true true false true false",
+			description: "toBool converts, or gives you false",
+			input:       `{{ toBool "yes" }} {{ toBool "TRUE" }} {{ toBool "off" }} {{ toBool 2 }} {{ toBool "nope" }}`,
+		},
+		TestStruct{
+			expected:    "10 1.5 true",
+			description: "the must* helpers convert",
+			input:       `{{ mustToInt "10" }} {{ mustToFloat "1.5" }} {{ mustToBool "on" }}`,
+		},
+		TestStruct{
+			expected:    "map slice string int invalid",
+			description: "kindOf gives you the kind",
+			input:       `{{ kindOf dict }} {{ kindOf list }} {{ kindOf "" }} {{ kindOf 1 }} {{ kindOf nil }}`,
+		},
+		TestStruct{
+			expected:    "map[string]interface {} []string",
+			description: "typeOf gives you the type",
+			input:       `{{ typeOf dict }} {{ split "a" "," | typeOf }}`,
+		},
+		TestStruct{
+			expected:    "true false",
+			description: "kindIs checks the kind",
+			input:       `{{ dict | kindIs "map" }} {{ "" | kindIs "map" }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
+
+func TestConvert__must(t *testing.T) {
+	os.Setenv("ENVP_TEST_BOOL", "nope")
+	defer os.Unsetenv("ENVP_TEST_BOOL")
+
+	for _, input := range []string{
+		`{{ mustToInt "nope" }}`,
+		`{{ mustToFloat "nope" }}`,
+		`{{ mustToBool "nope" }}`,
+		`{{ mustBoolEnv "envp_test_bool" }}`,
+	} {
+		tpl := template.New("envp")
+		New(tpl)
+
+		var out strings.Builder
+		template.Must(tpl.Parse(input))
+		assert.Error(t, tpl.Execute(&out, nil), input)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	// So inTimezone works in scratch images
+	_ "time/tzdata"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	durationRegex = regexp.MustCompile(`([0-9]*\.?[0-9]+)(d|w)`)
+	timeLayouts   = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"20060102",
+		time.RFC1123Z,
+		time.RFC1123,
+	}
+)
+
+// ParseEpoch parses unix seconds, like what's in
+// $SOURCE_DATE_EPOCH, into a time that's in UTC.
+func ParseEpoch(s string) (time.Time, error) {
+	i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse %q as unix seconds", s)
+	}
+
+	return time.Unix(i, 0).UTC(), nil
+}
+
+// ParseTime parses RFC3339, and the common date, and
+// date time layouts, without a zone it's UTC, so that
+// renders are the same everywhere, unix seconds need
+// an @ (like @1545696000) so 20240102 is still a date.
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "@") {
+		return ParseEpoch(s[1:])
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse %q as a time", s)
+}
+
+// Now gives you the time of the render, it's Time if
+// it's set (--now), or $SOURCE_DATE_EPOCH, it's held
+// so that every call in a render gives the same time.
+func (h *Helpers) Now() time.Time {
+	if h.Time.IsZero() {
+		h.Time = time.Now().Round(0)
+		if epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH"); ok && epoch != "" {
+			t, err := ParseEpoch(epoch)
+			if err != nil {
+				logrus.Fatalln(err)
+			}
+
+			h.Time = t
+		}
+	}
+
+	return h.Time
+}
+
+// toTime takes a time, unix seconds (as a number,
+// or a string with an @), or a string with a date.
+func toTime(v interface{}) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case *time.Time:
+		return *t
+	case string:
+		out, err := ParseTime(t)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		return out
+	}
+
+	n := toNum(v)
+	if n.isInt {
+		return time.Unix(n.i, 0).UTC()
+	}
+
+	// JSON, and YAML numbers are floats
+	sec, frac := math.Modf(n.f)
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC()
+}
+
+// toDuration takes a duration, seconds, or a string
+// like 1h30m, it also understands d (24h), and w (7d)
+func toDuration(v interface{}) time.Duration {
+	switch t := v.(type) {
+	case time.Duration:
+		return t
+	case string:
+		s := durationRegex.ReplaceAllStringFunc(t, func(m string) string {
+			unit, hours := m[len(m)-1:], 24.0
+			if unit == "w" {
+				hours *= 7
+			}
+
+			f, _ := strconv.ParseFloat(m[:len(m)-1], 64)
+			return strconv.FormatFloat(f*hours, 'f', -1, 64) + "h"
+		})
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		return d
+	}
+
+	return time.Duration(toNum(v).f * float64(time.Second))
+}
+
+// Date formats a time with a Go layout, like
+// "2006-01-02", the time is last for pipelines.
+func (h *Helpers) Date(layout string, t interface{}) string {
+	return toTime(t).Format(layout)
+}
+
+// UnixEpoch gives you a time as unix seconds
+func (h *Helpers) UnixEpoch(t interface{}) int {
+	return int(toTime(t).Unix())
+}
+
+// ToDate parses a string into a time, you can
+// give it a layout first, otherwise we'll guess.
+func (h *Helpers) ToDate(s ...string) time.Time {
+	switch len(s) {
+	case 1:
+		return toTime(s[0])
+	case 2:
+		t, err := time.Parse(s[0], s[1])
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		return t
+	}
+
+	logrus.Fatalln("toDate takes a string, or a layout, and a string")
+	return time.Time{}
+}
+
+// DateModify adds a duration (like +24h, -30m, or
+// +7d) to a time, and gives you the new time back.
+func (h *Helpers) DateModify(d string, t interface{}) time.Time {
+	return toTime(t).Add(toDuration(d))
+}
+
+// Duration formats seconds, or a duration as a
+// string, like 1h30m0s
+func (h *Helpers) Duration(d interface{}) string {
+	return toDuration(d).String()
+}
+
+// DurationRound rounds a duration, or the time since
+// a time to the biggest unit, like 2h, 3d, or 1y.
+func (h *Helpers) DurationRound(v interface{}) string {
+	var d time.Duration
+	if t, ok := v.(time.Time); ok {
+		d = h.Now().Sub(t)
+	} else {
+		d = toDuration(v)
+	}
+
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"y", 365 * 24 * time.Hour},
+		{"mo", 30 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	abs := time.Duration(math.Abs(float64(d)))
+	for _, u := range units {
+		if abs >= u.size {
+			out := fmt.Sprintf("%d%s", abs/u.size, u.name)
+			if d < 0 {
+				return "-" + out
+			}
+
+			return out
+		}
+	}
+
+	return "0s"
+}
+
+// InTimezone converts a time into the timezone,
+// like UTC, Local, or America/Chicago
+func (h *Helpers) InTimezone(tz string, t interface{}) time.Time {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return toTime(t).In(loc)
+}
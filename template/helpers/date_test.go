@@ -0,0 +1,95 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"os"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDates(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "2018-12-25 1545696000",
+			description: "now is the time you set",
+			input:       `{{ now | date "2006-01-02" }} {{ now | unixEpoch }}`,
+		},
+		TestStruct{
+			expected:    "2018-12-26T00:00:00Z 2018-12-31",
+			description: "dateModify adds durations, and days",
+			input:       `{{ now | dateModify "+24h" | date "2006-01-02T15:04:05Z07:00" }} {{ now | dateModify "+1w" | dateModify "-1d" | date "2006-01-02" }}`,
+		},
+		TestStruct{
+			expected:    "2018-01-02 2018-01-02 2018-03-04 1514851200",
+			description: "toDate guesses, or takes a layout",
+			input:       `{{ toDate "2018-01-02T03:04:05Z" | date "2006-01-02" }} {{ toDate "2018-01-02" | date "2006-01-02" }} {{ toDate "02/01/2006" "04/03/2018" | date "2006-01-02" }} {{ "2018-01-02" | toDate | unixEpoch }}`,
+		},
+		TestStruct{
+			expected:    "2018-12-25T00:00:00Z 2018-12-25T00:00:00.5Z",
+			description: "dates work with JSON numbers, they're floats",
+			input:       `{{ (fromJson "{\"t\": 1545696000}").t | date "2006-01-02T15:04:05.999Z07:00" }} {{ 1545696000.5 | date "2006-01-02T15:04:05.999Z07:00" }}`,
+		},
+		TestStruct{
+			expected:    "1h30m0s 1m30s 48h0m0s",
+			description: "duration formats seconds, and strings",
+			input:       `{{ duration "90m" }} {{ duration 90 }} {{ duration "2d" }}`,
+		},
+		TestStruct{
+			expected:    "2h 3d 1y -5m 0s",
+			description: "durationRound rounds to the biggest unit",
+			input:       `{{ durationRound "2h30m" }} {{ durationRound "3d5h" }} {{ durationRound "400d" }} {{ durationRound "-5m10s" }} {{ durationRound 0 }}`,
+		},
+		TestStruct{
+			expected:    "7d",
+			description: "durationRound works with a time",
+			input:       `{{ now | dateModify "-7d" | durationRound }}`,
+		},
+		TestStruct{
+			expected:    "2018-12-24 19:00 EST",
+			description: "inTimezone converts",
+			input:       `{{ now | inTimezone "America/New_York" | date "2006-01-02 15:04 MST" }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description, func(h *Helpers) {
+			h.Time = time.Date(2018, 12, 25, 0, 0, 0, 0, time.UTC)
+		})
+	}
+}
+
+func TestNow(t *testing.T) {
+	h := New(template.New("envp"))
+	assert.Equal(t, h.Now(), h.Now(), "it's the same in a render")
+
+	os.Setenv("SOURCE_DATE_EPOCH", "1545696000")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+	h = New(template.New("envp"))
+	assert.Equal(t, int64(1545696000), h.Now().Unix(),
+		"it uses $SOURCE_DATE_EPOCH")
+}
+
+func TestParseTime(t *testing.T) {
+	for _, s := range []string{"@1545696000", "2018-12-25T00:00:00Z", "2018-12-25 00:00:00", "2018-12-25", "20181225"} {
+		actual, err := ParseTime(s)
+		if assert.Nil(t, err, s) {
+			assert.Equal(t, int64(1545696000), actual.Unix(), s)
+		}
+	}
+
+	_, err := ParseTime("nope")
+	assert.Error(t, err)
+
+	_, err = ParseTime("1545696000")
+	assert.Error(t, err, "it needs an @ for unix seconds")
+}
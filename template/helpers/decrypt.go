@@ -0,0 +1,40 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"github.com/envygeeks/envp/crypt"
+	"github.com/sirupsen/logrus"
+)
+
+// cryptKeys loads the keys once, and holds them
+// so that we don't hit the env, or disk per value.
+func (h *Helpers) cryptKeys() *crypt.Keys {
+	if h.keys == nil {
+		keys, err := crypt.LoadKeys()
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		h.keys = keys
+	}
+
+	return h.keys
+}
+
+// Decrypt decrypts an ENC[...] value with the key,
+// or identity in your env, plain values pass through.
+func (h *Helpers) Decrypt(s string) string {
+	if !crypt.IsEncrypted(s) {
+		return s
+	}
+
+	b, err := crypt.Open(s, h.cryptKeys())
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"os"
+	"testing"
+	"text/template"
+
+	"github.com/envygeeks/envp/crypt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecrypt(t *testing.T) {
+	key, _ := crypt.GenerateKey()
+	recipient, identity, _ := crypt.GenerateIdentity()
+	os.Setenv(crypt.IdentityEnv, crypt.EncodeKey(identity))
+	os.Setenv(crypt.KeyEnv, crypt.EncodeKey(key))
+	defer os.Unsetenv(crypt.IdentityEnv)
+	defer os.Unsetenv(crypt.KeyEnv)
+
+	sealed, _ := crypt.Seal([]byte("hello"), key)
+	sealedTo, _ := crypt.SealTo([]byte("world"), recipient)
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	helpers := New(template.New("envp"))
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "hello",
+			description: "it decrypts with a key",
+			input:       sealed,
+		},
+		TestStruct{
+			expected:    "world",
+			description: "it decrypts with an identity",
+			input:       sealedTo,
+		},
+		TestStruct{
+			expected:    "plain",
+			description: "it passes plain values through",
+			input:       "plain",
+		},
+	} {
+		actual := helpers.Decrypt(test.input)
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
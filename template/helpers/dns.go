@@ -0,0 +1,196 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// So that the tests can use a fixture
+var resolvConfFile = "/etc/resolv.conf"
+
+const (
+	defaultDNSTimeout = 5 * time.Second
+)
+
+/**
+ * SRV is an SRV record, the target doesn't have
+ * the trailing dot, so you can use it in a config.
+ */
+type SRV struct {
+	Target   string
+	Port     int
+	Priority int
+	Weight   int
+}
+
+/**
+ * ResolvConf is what's in /etc/resolv.conf
+ */
+type ResolvConf struct {
+	Nameservers []string
+	Search      []string
+	Options     []string
+}
+
+// lookup runs a lookup with the timeout, and holds
+// on to the result, so that every call in a render
+// gets the same answer, and we only ask once.
+func (h *Helpers) lookup(key string, fn func(context.Context, *net.Resolver) (interface{}, error)) interface{} {
+	if v, ok := h.lookups[key]; ok {
+		return v
+	}
+
+	timeout := h.DNSTimeout
+	if timeout <= 0 {
+		timeout = defaultDNSTimeout
+	}
+
+	resolver := h.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	logrus.Debugf("looking up %s", key)
+	v, err := fn(ctx, resolver)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if h.lookups == nil {
+		h.lookups = map[string]interface{}{}
+	}
+
+	h.lookups[key] = v
+	return v
+}
+
+// LookupHost gives you the addresses of a host,
+// sorted, /etc/hosts is used too, like it'd be for
+// anything else, it stops the render if there's none.
+func (h *Helpers) LookupHost(name string) []string {
+	return h.lookup("host:"+name, func(ctx context.Context, r *net.Resolver) (interface{}, error) {
+		addrs, err := r.LookupHost(ctx, name)
+		sort.Strings(addrs)
+		return addrs, err
+	}).([]string)
+}
+
+// LookupIP gives you the IPs of a host, sorted, you
+// can give it ip4, or ip6 first to only get that version.
+func (h *Helpers) LookupIP(s ...string) []string {
+	network, name := "ip", ""
+	switch len(s) {
+	case 1:
+		name = s[0]
+	case 2:
+		network, name = s[0], s[1]
+	default:
+		logrus.Fatalln("lookupIP takes a host, or ip4/ip6, and a host")
+	}
+
+	return h.lookup(network+":"+name, func(ctx context.Context, r *net.Resolver) (interface{}, error) {
+		ips, err := r.LookupIP(ctx, network, name)
+		out := make([]string, len(ips))
+		for i, ip := range ips {
+			out[i] = ip.String()
+		}
+
+		sort.Strings(out)
+		return out, err
+	}).([]string)
+}
+
+// LookupSRV gives you the SRV records of a name, like
+// _http._tcp.web.service.consul, sorted by priority, and
+// then weight, which is the order you should use them.
+func (h *Helpers) LookupSRV(name string) []*SRV {
+	return h.lookup("srv:"+name, func(ctx context.Context, r *net.Resolver) (interface{}, error) {
+		_, records, err := r.LookupSRV(ctx, "", "", name)
+		out := make([]*SRV, len(records))
+		for i, rec := range records {
+			out[i] = &SRV{
+				Target:   strings.TrimSuffix(rec.Target, "."),
+				Priority: int(rec.Priority),
+				Weight:   int(rec.Weight),
+				Port:     int(rec.Port),
+			}
+		}
+
+		sort.SliceStable(out, func(i, j int) bool {
+			if out[i].Priority != out[j].Priority {
+				return out[i].Priority < out[j].Priority
+			}
+
+			return out[i].Weight > out[j].Weight
+		})
+
+		return out, err
+	}).([]*SRV)
+}
+
+// LookupCNAME gives you the canonical name of a host,
+// without the trailing dot, it's the host if there's none.
+func (h *Helpers) LookupCNAME(name string) string {
+	return h.lookup("cname:"+name, func(ctx context.Context, r *net.Resolver) (interface{}, error) {
+		cname, err := r.LookupCNAME(ctx, name)
+		return strings.TrimSuffix(cname, "."), err
+	}).(string)
+}
+
+// ResolvConf gives you the nameservers, search domains,
+// and options out of /etc/resolv.conf, a domain is a search
+// domain if there's no search, it's empty if there's no file.
+func (h *Helpers) ResolvConf() *ResolvConf {
+	out := &ResolvConf{
+		Nameservers: []string{},
+		Search:      []string{},
+		Options:     []string{},
+	}
+
+	f, err := os.Open(resolvConfFile)
+	if err != nil {
+		logrus.Debugln(err)
+		return out
+	}
+
+	defer f.Close()
+	domain := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+
+		switch fields[0] {
+		case "nameserver":
+			out.Nameservers = append(out.Nameservers, fields[1])
+		case "search":
+			out.Search = fields[1:]
+		case "domain":
+			domain = fields[1]
+		case "options":
+			out.Options = append(out.Options, fields[1:]...)
+		}
+	}
+
+	if len(out.Search) == 0 && domain != "" {
+		out.Search = []string{domain}
+	}
+
+	return out
+}
@@ -0,0 +1,255 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dnsA     = 1
+	dnsCNAME = 5
+	dnsAAAA  = 28
+	dnsSRV   = 33
+)
+
+/**
+ * dnsServer is a local DNS stand-in, it answers
+ * from the records, and counts the questions that
+ * it gets, so we can check the cache.
+ */
+type dnsServer struct {
+	conn    net.PacketConn
+	records map[string][][]byte
+	asked   map[string]int
+}
+
+// dnsName encodes a name, without compression
+func dnsName(name string) []byte {
+	out := []byte{}
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		out = append(out, byte(len(label)))
+		out = append(out, label...)
+	}
+
+	return append(out, 0)
+}
+
+// dnsRR encodes a record, with a ttl of 60
+func dnsRR(name string, kind uint16, data []byte) []byte {
+	out := dnsName(name)
+	out = binary.BigEndian.AppendUint16(out, kind)
+	out = binary.BigEndian.AppendUint16(out, 1)
+	out = binary.BigEndian.AppendUint32(out, 60)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(data)))
+	return append(out, data...)
+}
+
+func srvData(priority, weight, port uint16, target string) []byte {
+	out := binary.BigEndian.AppendUint16(nil, priority)
+	out = binary.BigEndian.AppendUint16(out, weight)
+	out = binary.BigEndian.AppendUint16(out, port)
+	return append(out, dnsName(target)...)
+}
+
+// serve answers questions, until it's closed
+func (d *dnsServer) serve() {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := d.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		// The question is after the 12 byte header,
+		// it's the name, and then the type, and class.
+		msg, end := buf[:n], 12
+		for end < len(msg) && msg[end] != 0 {
+			end += int(msg[end]) + 1
+		}
+
+		end += 5
+		if end > len(msg) {
+			continue
+		}
+
+		labels, i := []string{}, 12
+		for msg[i] != 0 {
+			labels = append(labels, string(msg[i+1:i+1+int(msg[i])]))
+			i += int(msg[i]) + 1
+		}
+
+		kind := binary.BigEndian.Uint16(msg[end-4:])
+		key := strings.ToLower(strings.Join(labels, ".")) + "/" + map[uint16]string{
+			dnsA: "A", dnsAAAA: "AAAA", dnsCNAME: "CNAME", dnsSRV: "SRV",
+		}[kind]
+
+		d.asked[key]++
+		answers := d.records[key]
+		out := append([]byte{}, msg[:2]...)
+		out = append(out, 0x81, 0x80, 0, 1)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(answers)))
+		out = append(out, 0, 0, 0, 0)
+		out = append(out, msg[12:end]...)
+		for _, answer := range answers {
+			out = append(out, answer...)
+		}
+
+		d.conn.WriteTo(out, addr)
+	}
+}
+
+// resolver gives you a resolver that only talks to us
+func (d *dnsServer) resolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "udp", d.conn.LocalAddr().String())
+		},
+	}
+}
+
+func TestLookup(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	defer conn.Close()
+	server := &dnsServer{
+		conn:  conn,
+		asked: map[string]int{},
+		records: map[string][][]byte{
+			"web.envp.test/A": [][]byte{
+				dnsRR("web.envp.test", dnsA, []byte{10, 0, 0, 2}),
+				dnsRR("web.envp.test", dnsA, []byte{10, 0, 0, 1}),
+			},
+			"web.envp.test/AAAA": [][]byte{
+				dnsRR("web.envp.test", dnsAAAA, net.ParseIP("fd00::1")),
+			},
+			"www.envp.test/A": [][]byte{
+				dnsRR("www.envp.test", dnsCNAME, dnsName("web.envp.test")),
+				dnsRR("web.envp.test", dnsA, []byte{10, 0, 0, 1}),
+			},
+			"_http._tcp.web.envp.test/SRV": [][]byte{
+				dnsRR("_http._tcp.web.envp.test", dnsSRV, srvData(20, 0, 8080, "c.envp.test")),
+				dnsRR("_http._tcp.web.envp.test", dnsSRV, srvData(10, 5, 8081, "b.envp.test")),
+				dnsRR("_http._tcp.web.envp.test", dnsSRV, srvData(10, 50, 8082, "a.envp.test")),
+			},
+		},
+	}
+
+	go server.serve()
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "10.0.0.1,10.0.0.2,fd00::1",
+			description: "lookupHost gives you the addresses, sorted",
+			input:       `{{ join "," (lookupHost "web.envp.test.") }}`,
+		},
+		TestStruct{
+			expected:    "10.0.0.1,10.0.0.2 fd00::1",
+			description: "lookupIP can take ip4, or ip6",
+			input:       `{{ join "," (lookupIP "ip4" "web.envp.test.") }} {{ join "," (lookupIP "ip6" "web.envp.test.") }}`,
+		},
+		TestStruct{
+			expected:    "a.envp.test:8082 b.envp.test:8081 c.envp.test:8080 ",
+			description: "lookupSRV gives you the records, by priority",
+			input:       `{{ range lookupSRV "_http._tcp.web.envp.test." }}{{ .Target }}:{{ .Port }} {{ end }}`,
+		},
+		TestStruct{
+			expected:    "web.envp.test",
+			description: "lookupCNAME gives you the canonical name",
+			input:       `{{ lookupCNAME "www.envp.test." }}`,
+		},
+		TestStruct{
+			expected:    "127.0.0.1",
+			description: "lookupIP uses /etc/hosts",
+			input:       `{{ range lookupIP "ip4" "localhost" }}{{ . }}{{ end }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description, func(h *Helpers) {
+			h.resolver = server.resolver()
+		})
+	}
+
+	server.asked = map[string]int{}
+	tpl := template.New("envp")
+	h := New(tpl)
+	h.resolver = server.resolver()
+
+	var out strings.Builder
+	template.Must(tpl.Parse(`{{ lookupIP "ip4" "web.envp.test." }}{{ lookupIP "ip4" "web.envp.test." }}`))
+	assert.Nil(t, tpl.Execute(&out, nil))
+	assert.Equal(t, 1, server.asked["web.envp.test/A"],
+		"it only asks once in a render")
+}
+
+func TestResolvConf(t *testing.T) {
+	dir := fixtures(t, map[string]string{
+		"search": "# comment\nnameserver 10.0.0.2\nnameserver fd00::53\n" +
+			"search svc.cluster.local cluster.local\noptions ndots:5 timeout:2\n",
+		"domain": "nameserver 10.0.0.2\ndomain envp.test\n",
+	})
+
+	defer os.RemoveAll(dir)
+	old := resolvConfFile
+	defer func() { resolvConfFile = old }()
+
+	type TestStruct struct {
+		expected    *ResolvConf
+		description string
+		file        string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected: &ResolvConf{
+				Nameservers: []string{"10.0.0.2", "fd00::53"},
+				Search:      []string{"svc.cluster.local", "cluster.local"},
+				Options:     []string{"ndots:5", "timeout:2"},
+			},
+			description: "it reads nameservers, search, and options",
+			file:        "search",
+		},
+		TestStruct{
+			expected: &ResolvConf{
+				Nameservers: []string{"10.0.0.2"},
+				Search:      []string{"envp.test"},
+				Options:     []string{},
+			},
+			description: "domain is search, if there's no search",
+			file:        "domain",
+		},
+		TestStruct{
+			expected: &ResolvConf{
+				Nameservers: []string{},
+				Search:      []string{},
+				Options:     []string{},
+			},
+			description: "it's empty if there's no file",
+			file:        "nope",
+		},
+	} {
+		resolvConfFile = filepath.Join(dir, test.file)
+		actual := New(template.New("envp")).ResolvConf()
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
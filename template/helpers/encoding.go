@@ -0,0 +1,97 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sha1 hex encodes the sha1 sum of a string
+func (h *Helpers) Sha1(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sha256 hex encodes the sha256 sum of a string
+func (h *Helpers) Sha256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sha512 hex encodes the sha512 sum of a string
+func (h *Helpers) Sha512(s string) string {
+	sum := sha512.Sum512([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Md5 hex encodes the md5 sum of a string
+func (h *Helpers) Md5(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// HmacSha256 hex encodes the HMAC-SHA256 of a string,
+// the key is first so that you can pipe the string in.
+func (h *Helpers) HmacSha256(key, s string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Crc32 hex encodes the IEEE crc32 of a string
+func (h *Helpers) Crc32(s string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(s)))
+}
+
+// B64enc base64 encodes a string
+func (h *Helpers) B64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// B64urlenc base64 encodes a string for URLs
+func (h *Helpers) B64urlenc(s string) string {
+	return base64.URLEncoding.EncodeToString([]byte(s))
+}
+
+// B64dec base64 decodes a string
+func (h *Helpers) B64dec(s string) string {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return string(b)
+}
+
+// B32enc base32 encodes a string
+func (h *Helpers) B32enc(s string) string {
+	return base32.StdEncoding.EncodeToString([]byte(s))
+}
+
+// Hexenc hex encodes a string
+func (h *Helpers) Hexenc(s string) string {
+	return hex.EncodeToString([]byte(s))
+}
+
+// Hexdec hex decodes a string
+func (h *Helpers) Hexdec(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashes(t *testing.T) {
+	helpers := New(template.New("envp"))
+	type TestStruct struct {
+		expected    string
+		description string
+		fn          func(string) string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d",
+			description: "it works with sha1",
+			fn:          helpers.Sha1,
+		},
+		TestStruct{
+			expected:    "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			description: "it works with sha256",
+			fn:          helpers.Sha256,
+		},
+		TestStruct{
+			expected: "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca7" +
+				"2323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043",
+			description: "it works with sha512",
+			fn:          helpers.Sha512,
+		},
+		TestStruct{
+			expected:    "5d41402abc4b2a76b9719d911017c592",
+			description: "it works with md5",
+			fn:          helpers.Md5,
+		},
+		TestStruct{
+			expected:    "3610a686",
+			description: "it works with crc32",
+			fn:          helpers.Crc32,
+		},
+	} {
+		actual := test.fn("hello")
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestHmacSha256(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.HmacSha256("key", "hello")
+	assert.Equal(t, "9307b3b915efb5171ff14d8cb55fbcc798c6c0ef1456d66ded1a6aa723a58b7b",
+		actual)
+}
+
+func TestEncodings(t *testing.T) {
+	helpers := New(template.New("envp"))
+	type TestStruct struct {
+		input       string
+		expected    string
+		description string
+		fn          func(string) string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			input:       "hello",
+			expected:    "aGVsbG8=",
+			description: "it base64 encodes",
+			fn:          helpers.B64enc,
+		},
+		TestStruct{
+			input:       "aGVsbG8=",
+			expected:    "hello",
+			description: "it base64 decodes",
+			fn:          helpers.B64dec,
+		},
+		TestStruct{
+			input:       "\xfb\xff",
+			expected:    "-_8=",
+			description: "it base64 encodes for urls",
+			fn:          helpers.B64urlenc,
+		},
+		TestStruct{
+			input:       "hello",
+			expected:    "NBSWY3DP",
+			description: "it base32 encodes",
+			fn:          helpers.B32enc,
+		},
+		TestStruct{
+			input:       "hello",
+			expected:    "68656c6c6f",
+			description: "it hex encodes",
+			fn:          helpers.Hexenc,
+		},
+		TestStruct{
+			input:       "68656c6c6f",
+			expected:    "hello",
+			description: "it hex decodes",
+			fn:          helpers.Hexdec,
+		},
+	} {
+		actual := test.fn(test.input)
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
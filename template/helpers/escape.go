@@ -0,0 +1,162 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Raw is a string that's already escaped, so
+// that auto-escaping leaves it alone for you.
+type Raw string
+
+// Raw marks a string as already escaped
+func (h *Helpers) Raw(s string) Raw {
+	return Raw(s)
+}
+
+var (
+	// Formats maps file extensions to the formats
+	// that we know how to automatically escape.
+	Formats = map[string]string{
+		"json":  "json",
+		"yaml":  "yaml",
+		"yml":   "yaml",
+		"toml":  "toml",
+		"ini":   "ini",
+		"xml":   "xml",
+		"html":  "html",
+		"htm":   "html",
+		"sh":    "shell",
+		"bash":  "shell",
+		"shell": "shell",
+	}
+
+	// Partials are the funcs that run a template by
+	// name, the partial is escaped itself, so what they
+	// give back is already escaped, like include.
+	Partials = []string{
+		"include",
+		"templateString",
+		"strippedTemplate",
+		"indentedTemplate",
+		"fixIndentedTemplate",
+		"templateWithNewline",
+		"indentedTemplateWithNewline",
+	}
+
+	// safe are the funcs whose output is already
+	// escaped for a format, so we don't do it twice.
+	safe = map[string][]string{
+		"json":  {"quoteJson", "toJson", "toPrettyJson"},
+		"yaml":  {"quoteYaml", "toYaml"},
+		"toml":  {"quoteToml", "toToml"},
+		"ini":   {"quoteIni", "toIni"},
+		"xml":   {"quoteXml"},
+		"shell": {"quoteShell"},
+	}
+)
+
+// FormatOf detects the format from a file name, it
+// looks at the extension under the template extension
+// so app.json.gohtml is json, and so is app.json
+func FormatOf(name string) string {
+	name = strings.ToLower(filepath.Base(name))
+	for _, ext := range []string{".gohtml", ".gotxt", ".tmpl", ".tpl"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	return Formats[strings.TrimPrefix(filepath.Ext(name), ".")]
+}
+
+// EscaperFor is the name of the func that escapes a
+// format, it's "" if there is none (like for html.)
+func EscaperFor(format string) string {
+	if _, ok := safe[format]; !ok {
+		return ""
+	}
+
+	return "_escape_" + format
+}
+
+// IsSafe tells you if a func's output is already
+// escaped for the format, so you can skip escaping, the
+// partials, and tpl's templates are escaped themselves.
+func IsSafe(format, name string) bool {
+	if name == "raw" || name == "tpl" || name == EscaperFor(format) {
+		return true
+	}
+
+	for _, v := range append(Partials, safe[format]...) {
+		if v == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scalar tells you if a value is a plain scalar
+func scalar(v interface{}) bool {
+	switch v.(type) {
+	case bool, int, int8, int16, int32, int64, uint, uint8,
+		uint16, uint32, uint64, float32, float64:
+		return true
+	}
+
+	return false
+}
+
+// escaper wraps a quoting func so that it leaves
+// Raw alone, and so numbers, and bools stay as is.
+func escaper(quote func(string) string) func(interface{}) string {
+	return func(v interface{}) string {
+		switch t := v.(type) {
+		case Raw:
+			return string(t)
+		case string:
+			return quote(t)
+		}
+
+		if scalar(v) {
+			return fmt.Sprint(v)
+		}
+
+		return quote(fmt.Sprint(v))
+	}
+}
+
+// escapers are the funcs that auto-escaping adds
+// to the end of every action that outputs a value.
+func (h *Helpers) escapers() map[string]interface{} {
+	structured := func(quote func(string) string) func(interface{}) string {
+		esc := escaper(quote)
+		return func(v interface{}) string {
+			switch v.(type) {
+			case map[string]interface{}, map[interface{}]interface{}, []interface{}, map[string]string, []string:
+				return h.ToJSON(v)
+			}
+
+			return esc(v)
+		}
+	}
+
+	return map[string]interface{}{
+		EscaperFor("json"):  structured(h.QuoteJSON),
+		EscaperFor("yaml"):  structured(h.QuoteYAML),
+		EscaperFor("toml"):  escaper(h.QuoteTOML),
+		EscaperFor("ini"):   escaper(h.QuoteINI),
+		EscaperFor("shell"): escaper(h.QuoteShell),
+		EscaperFor("xml"): func(v interface{}) string {
+			if r, ok := v.(Raw); ok {
+				return string(r)
+			}
+
+			return h.QuoteXML(fmt.Sprint(v))
+		},
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatOf(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		name        string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "json",
+			description: "it works with .json.gohtml",
+			name:        "/etc/app.json.gohtml",
+		},
+		TestStruct{
+			expected:    "yaml",
+			description: "it works with .yml",
+			name:        "app.YML",
+		},
+		TestStruct{
+			expected:    "shell",
+			description: "it works with .sh.gotxt",
+			name:        "run.sh.gotxt",
+		},
+		TestStruct{
+			expected:    "",
+			description: "it's blank for unknown",
+			name:        "app.gohtml",
+		},
+	} {
+		actual := FormatOf(test.name)
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestIsSafe(t *testing.T) {
+	assert.True(t, IsSafe("json", "raw"))
+	assert.True(t, IsSafe("yaml", "include"))
+	assert.True(t, IsSafe("json", "toJson"))
+	assert.False(t, IsSafe("yaml", "toJson"))
+	assert.False(t, IsSafe("json", "env"))
+}
@@ -0,0 +1,192 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// realpath gives you the absolute path, with the
+// symlinks resolved (up to the part that exists) so
+// that a link can't get you out of the allowed roots.
+func realpath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	rest := ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+
+		dir := filepath.Dir(abs)
+		if dir == abs {
+			return filepath.Join(abs, rest)
+		}
+
+		rest = filepath.Join(filepath.Base(abs), rest)
+		abs = dir
+	}
+}
+
+// within tells you if path is root, or inside of it
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// jailed tells you if the path is inside of one of
+// the roots, with it's real path, with no roots it's not.
+func jailed(roots []string, path string) (string, bool) {
+	resolved := realpath(path)
+	for _, root := range roots {
+		if within(realpath(root), resolved) {
+			return resolved, true
+		}
+	}
+
+	return resolved, false
+}
+
+// allowed tells you if the path is inside of one of
+// the roots in AllowRead, with it's real path.
+func (h *Helpers) allowed(path string) (string, bool) {
+	return jailed(h.AllowRead, path)
+}
+
+// readable makes sure that you can read the path
+func (h *Helpers) readable(path string) string {
+	resolved, ok := h.allowed(path)
+	if !ok {
+		logrus.Fatalf("%s isn't readable, you can allow it with --allow-read", path)
+	}
+
+	return resolved
+}
+
+// writable makes sure that you can write the path
+func (h *Helpers) writable(path string) string {
+	resolved, ok := jailed(h.AllowWrite, path)
+	if !ok {
+		logrus.Fatalf("%s isn't writable, you can allow it with --allow-write", path)
+	}
+
+	return resolved
+}
+
+// ReadFile reads a file, like a CA bundle
+func (h *Helpers) ReadFile(path string) string {
+	b, err := ioutil.ReadFile(h.readable(path))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return string(b)
+}
+
+// ReadLines reads a file into a list of lines
+func (h *Helpers) ReadLines(path string) []string {
+	f, err := os.Open(h.readable(path))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	defer f.Close()
+	out := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		out = append(out, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return out
+}
+
+// FileExists tells you if a file (or a dir) exists
+func (h *Helpers) FileExists(path string) bool {
+	_, err := os.Stat(h.readable(path))
+	return err == nil
+}
+
+// IsDir tells you if the path is a dir
+func (h *Helpers) IsDir(path string) bool {
+	info, err := os.Stat(h.readable(path))
+	return err == nil && info.IsDir()
+}
+
+// Glob gives you the paths that match the pattern,
+// sorted, paths outside of the roots are left out.
+func (h *Helpers) Glob(pattern string) []string {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	out := []string{}
+	for _, m := range matches {
+		if _, ok := h.allowed(m); ok {
+			out = append(out, m)
+		}
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// ReadDir gives you the names in a dir, sorted
+func (h *Helpers) ReadDir(path string) []string {
+	infos, err := ioutil.ReadDir(h.readable(path))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	out := make([]string, len(infos))
+	for i, info := range infos {
+		out[i] = info.Name()
+	}
+
+	return out
+}
+
+// Basename gives you the last part of a path
+func (h *Helpers) Basename(path string) string {
+	return filepath.Base(path)
+}
+
+// Dirname gives you all but the last part of a path
+func (h *Helpers) Dirname(path string) string {
+	return filepath.Dir(path)
+}
+
+// Ext gives you the extension of a path, like .pem
+func (h *Helpers) Ext(path string) string {
+	return filepath.Ext(path)
+}
+
+// Abs gives you the absolute path
+func (h *Helpers) Abs(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return abs
+}
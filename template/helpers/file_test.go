@@ -0,0 +1,106 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "envp")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, "certs"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "certs", "ca.pem"), []byte("ca\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "certs", "b.pem"), []byte("b\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "hosts"), []byte("a\nb\n"), 0644)
+
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "ca\n",
+			description: "readFile reads files",
+			input:       `{{ readFile "DIR/certs/ca.pem" }}`,
+		},
+		TestStruct{
+			expected:    `["a","b"]`,
+			description: "readLines reads lines",
+			input:       `{{ readLines "DIR/hosts" | toJson }}`,
+		},
+		TestStruct{
+			expected:    "true false true false",
+			description: "fileExists, and isDir",
+			input:       `{{ fileExists "DIR/hosts" }} {{ fileExists "DIR/nope" }} {{ isDir "DIR/certs" }} {{ isDir "DIR/hosts" }}`,
+		},
+		TestStruct{
+			expected:    "b.pem,ca.pem",
+			description: "glob globs",
+			input:       `{{ range $i, $f := glob "DIR/certs/*.pem" }}{{ if $i }},{{ end }}{{ basename $f }}{{ end }}`,
+		},
+		TestStruct{
+			expected:    `["certs","hosts"]`,
+			description: "readDir gives you the names",
+			input:       `{{ readDir "DIR" | toJson }}`,
+		},
+		TestStruct{
+			expected:    "ca.pem /etc/ssl .pem",
+			description: "basename, dirname, and ext",
+			input:       `{{ basename "/etc/ssl/ca.pem" }} {{ dirname "/etc/ssl/ca.pem" }} {{ ext "/etc/ssl/ca.pem" }}`,
+		},
+	} {
+		assertRender(t, strings.Replace(test.input, "DIR", dir, -1), test.expected, test.description, func(h *Helpers) {
+			h.AllowRead = []string{dir}
+		})
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "envp")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "root")
+	os.MkdirAll(root, 0755)
+	os.Symlink("/etc", filepath.Join(root, "etc"))
+	ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0600)
+
+	h := New(template.New("envp"))
+	h.AllowRead = []string{root}
+	for path, expected := range map[string]bool{
+		root:                                 true,
+		filepath.Join(root, "a", "b"):        true,
+		filepath.Join(root, "..", "secret"):  false,
+		filepath.Join(dir, "rootx"):          false,
+		filepath.Join(root, "etc", "passwd"): false,
+		filepath.Join(root, "etc", "nope"):   false,
+		"/etc/passwd":                        false,
+	} {
+		_, actual := h.allowed(path)
+		assert.Equal(t, expected, actual, path)
+	}
+
+	h.AllowRead = nil
+	_, actual := h.allowed(root)
+	assert.False(t, actual, "it allows nothing by default")
+	assert.Empty(t, h.Glob(filepath.Join(dir, "*")),
+		"glob leaves out what you can't read")
+}
@@ -0,0 +1,340 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Normalize turns map[interface{}]interface{} (which is
+// what YAML gives you) and typed maps, and slices (like
+// map[string]int, or []string) into map[string]interface{}
+// and []interface{} so every format can encode them.
+func Normalize(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		out := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			out[fmt.Sprint(k.Interface())] = Normalize(rv.MapIndex(k).Interface())
+		}
+
+		return out
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = Normalize(rv.Index(i).Interface())
+		}
+
+		return out
+	}
+
+	return v
+}
+
+// sortedKeys gives you the keys of a map sorted
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// toMap makes sure that you gave us a map
+func toMap(v interface{}, format string) map[string]interface{} {
+	m, ok := Normalize(v).(map[string]interface{})
+	if !ok {
+		logrus.Fatalf("%s needs a map, not %T", format, v)
+	}
+
+	return m
+}
+
+func jsonEncode(v interface{}, indent string) string {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(Normalize(v)); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// ToJSON encodes a value as JSON, keys are sorted
+func (h *Helpers) ToJSON(v interface{}) string {
+	return jsonEncode(v, "")
+}
+
+// ToPrettyJSON encodes a value as indented JSON
+func (h *Helpers) ToPrettyJSON(v interface{}) string {
+	return jsonEncode(v, "  ")
+}
+
+// FromJSON decodes JSON into maps, and slices
+func (h *Helpers) FromJSON(s string) interface{} {
+	var out interface{}
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return out
+}
+
+// ToYAML encodes a value as YAML, keys are sorted
+func (h *Helpers) ToYAML(v interface{}) string {
+	b, err := yaml.Marshal(Normalize(v))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// FromYAML decodes YAML into maps, and slices
+func (h *Helpers) FromYAML(s string) interface{} {
+	var out interface{}
+	if err := yaml.Unmarshal([]byte(s), &out); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return Normalize(out)
+}
+
+// ToTOML encodes a map as TOML, keys are sorted
+func (h *Helpers) ToTOML(v interface{}) string {
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Encode(toMap(v, "toToml")); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// FromTOML decodes TOML into maps, and slices
+func (h *Helpers) FromTOML(s string) interface{} {
+	var out map[string]interface{}
+	if _, err := toml.Decode(s, &out); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return Normalize(out)
+}
+
+// iniString quotes a string with quoteEscaped if
+// a reader would see it differently, like a newline, a
+// comment, an =, a quote, or spaces at either end of it.
+func iniString(s, special string) string {
+	quote := strings.ContainsAny(s, special) || strings.TrimSpace(s) != s ||
+		strings.IndexFunc(s, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0
+	if quote {
+		return quoteEscaped(s)
+	}
+
+	return s
+}
+
+// plain formats a value for a flat format, null has
+// no way to be said in INI, or properties, so it's empty.
+func plain(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// iniValue formats a value for INI, slices are joined
+// with a comma, like most readers, so a comma in one of
+// them gets it quoted, the same as a special character.
+func iniValue(v interface{}) string {
+	if l, ok := v.([]interface{}); ok {
+		out := make([]string, len(l))
+		for i, vv := range l {
+			out[i] = iniString(plain(vv), `=;#"\,`)
+		}
+
+		return strings.Join(out, ",")
+	}
+
+	return iniString(plain(v), `=;#"\`)
+}
+
+// writeIni writes the values of a section, and then
+// any maps inside of it as [section.sub] sections.
+func writeIni(buf *bytes.Buffer, section string, m map[string]interface{}) {
+	keys := sortedKeys(m)
+	if section != "" {
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+
+		fmt.Fprintf(buf, "[%s]\n", section)
+	}
+
+	for _, k := range keys {
+		if _, ok := m[k].(map[string]interface{}); !ok {
+			fmt.Fprintf(buf, "%s = %s\n", k, iniValue(m[k]))
+		}
+	}
+
+	for _, k := range keys {
+		if sub, ok := m[k].(map[string]interface{}); ok {
+			name := k
+			if section != "" {
+				name = section + "." + k
+			}
+
+			writeIni(buf, name, sub)
+		}
+	}
+}
+
+// ToINI encodes a map as INI, nested maps become
+// sections, and deeper maps become [dotted.sections]
+func (h *Helpers) ToINI(v interface{}) string {
+	buf := &bytes.Buffer{}
+	writeIni(buf, "", toMap(v, "toIni"))
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// ParseINI decodes INI into a map, [dotted.sections]
+// become nested maps, and all of the values are strings,
+// double quoted values are unescaped, like toIni writes,
+// a key can use = or :, and a bare key is an empty value.
+func ParseINI(s string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	section := out
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = out
+			for _, k := range strings.Split(strings.Trim(line, "[]"), ".") {
+				sub, ok := section[k].(map[string]interface{})
+				if !ok {
+					sub = map[string]interface{}{}
+					section[k] = sub
+				}
+
+				section = sub
+			}
+
+			continue
+		}
+
+		k, v := line, ""
+		if i := strings.IndexAny(line, "=:"); i >= 0 {
+			k, v = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+
+		if k == "" {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+
+		if len(v) > 1 && v[0] == '"' && v[len(v)-1] == '"' {
+			if uv, err := strconv.Unquote(v); err == nil {
+				v = uv
+			} else {
+				v = v[1 : len(v)-1]
+			}
+		}
+
+		section[k] = v
+	}
+
+	return out, scanner.Err()
+}
+
+// FromINI decodes INI into a map, [dotted.sections]
+// become nested maps, and all values are strings.
+func (h *Helpers) FromINI(s string) interface{} {
+	out, err := ParseINI(s)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return out
+}
+
+// flatten flattens maps, and slices into dotted keys
+func flatten(prefix string, v interface{}, out map[string]string) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+
+		return prefix + "." + k
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range t {
+			flatten(join(k), vv, out)
+		}
+	case []interface{}:
+		for i, vv := range t {
+			flatten(join(fmt.Sprint(i)), vv, out)
+		}
+	default:
+		out[prefix] = plain(v)
+	}
+}
+
+var (
+	propertiesKey = strings.NewReplacer(`\`, `\\`, " ", `\ `, "=", `\=`,
+		":", `\:`, "#", `\#`, "!", `\!`, "\n", `\n`, "\t", `\t`)
+	propertiesValue = strings.NewReplacer(`\`, `\\`, "\n", `\n`,
+		"\r", `\r`, "\t", `\t`)
+)
+
+// ToProperties encodes a map as Java properties,
+// nested maps, and slices become dotted keys.
+func (h *Helpers) ToProperties(v interface{}) string {
+	flat := map[string]string{}
+	flatten("", toMap(v, "toProperties"), flat)
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+
+	var out []string
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := propertiesValue.Replace(flat[k])
+		if strings.HasPrefix(v, " ") {
+			v = `\` + v
+		}
+
+		out = append(out, propertiesKey.Replace(k)+"="+v)
+	}
+
+	return strings.Join(out, "\n")
+}
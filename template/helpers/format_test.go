@@ -0,0 +1,212 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	formatData = map[string]interface{}{
+		"name": "envp",
+		"port": 8080,
+		"tags": []interface{}{"a", "b"},
+		"db": map[string]interface{}{
+			"host": "db.local",
+			"url":  "a<b&c",
+		},
+	}
+)
+
+func TestToFormats(t *testing.T) {
+	helpers := New(template.New("envp"))
+	type TestStruct struct {
+		expected    string
+		description string
+		fn          func(interface{}) string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    `{"db":{"host":"db.local","url":"a<b&c"},"name":"envp","port":8080,"tags":["a","b"]}`,
+			description: "it encodes json with sorted keys",
+			fn:          helpers.ToJSON,
+		},
+		TestStruct{
+			expected:    "{\n  \"db\": {\n    \"host\": \"db.local\",\n    \"url\": \"a<b&c\"\n  },\n  \"name\": \"envp\",\n  \"port\": 8080,\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}",
+			description: "it encodes pretty json",
+			fn:          helpers.ToPrettyJSON,
+		},
+		TestStruct{
+			expected:    "db:\n  host: db.local\n  url: a<b&c\nname: envp\nport: 8080\ntags:\n- a\n- b",
+			description: "it encodes yaml with sorted keys",
+			fn:          helpers.ToYAML,
+		},
+		TestStruct{
+			expected:    "name = \"envp\"\nport = 8080\ntags = [\"a\", \"b\"]\n\n[db]\n  host = \"db.local\"\n  url = \"a<b&c\"",
+			description: "it encodes toml with sorted keys",
+			fn:          helpers.ToTOML,
+		},
+		TestStruct{
+			expected:    "name = envp\nport = 8080\ntags = a,b\n\n[db]\nhost = db.local\nurl = a<b&c",
+			description: "it encodes ini with sections",
+			fn:          helpers.ToINI,
+		},
+		TestStruct{
+			expected:    "db.host=db.local\ndb.url=a<b&c\nname=envp\nport=8080\ntags.0=a\ntags.1=b",
+			description: "it encodes properties with dotted keys",
+			fn:          helpers.ToProperties,
+		},
+	} {
+		actual := test.fn(formatData)
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestFromFormats(t *testing.T) {
+	helpers := New(template.New("envp"))
+	type TestStruct struct {
+		input       string
+		description string
+		fn          func(string) interface{}
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			input:       `{"db": {"host": "db.local"}}`,
+			description: "it decodes json",
+			fn:          helpers.FromJSON,
+		},
+		TestStruct{
+			input:       "db:\n  host: db.local",
+			description: "it decodes yaml into string maps",
+			fn:          helpers.FromYAML,
+		},
+		TestStruct{
+			input:       "[db]\nhost = \"db.local\"",
+			description: "it decodes toml",
+			fn:          helpers.FromTOML,
+		},
+		TestStruct{
+			input:       "; comment\n[db]\nhost = \"db.local\"",
+			description: "it decodes ini",
+			fn:          helpers.FromINI,
+		},
+	} {
+		expected := map[string]interface{}{"db": map[string]interface{}{"host": "db.local"}}
+		actual := test.fn(test.input)
+		assert.Equal(t, expected, actual,
+			test.description)
+	}
+}
+
+func TestFromINI(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.FromINI("top = 1\n[a.b]\nc = d\n# comment\n[e]\nf=g\nh: http://i\nj\nk = l:m")
+	assert.Equal(t, map[string]interface{}{
+		"top": "1",
+		"a":   map[string]interface{}{"b": map[string]interface{}{"c": "d"}},
+		"e": map[string]interface{}{
+			"f": "g",
+			"h": "http://i",
+			"j": "",
+			"k": "l:m",
+		},
+	}, actual)
+}
+
+func TestToProperties(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.ToProperties(map[string]interface{}{
+		"a key": " value\nline",
+	})
+
+	assert.Equal(t, `a\ key=\ value\nline`,
+		actual)
+
+	actual = helpers.ToProperties(map[string]interface{}{
+		"a": nil,
+	})
+
+	assert.Equal(t, "a=", actual,
+		"it leaves null empty")
+}
+
+func TestToINI(t *testing.T) {
+	helpers := New(template.New("envp"))
+	type TestStruct struct {
+		expected    string
+		description string
+		input       interface{}
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "a = \"x\\ny = z\"\nb = \"; not a comment\"\nc = \" padded \"\nd = \"C:\\\\dir\"",
+			description: "it quotes values that a reader would see differently",
+			input: map[string]interface{}{
+				"a": "x\ny = z",
+				"b": "; not a comment",
+				"c": " padded ",
+				"d": `C:\dir`,
+			},
+		},
+		TestStruct{
+			expected:    "a = x,\"y,z\"",
+			description: "it quotes list items with a comma",
+			input:       map[string]interface{}{"a": []string{"x", "y,z"}},
+		},
+		TestStruct{
+			expected:    "a = 1\n\n[b]\nc = 2",
+			description: "it normalizes typed maps",
+			input: map[string]interface{}{
+				"a": 1,
+				"b": map[string]int{"c": 2},
+			},
+		},
+		TestStruct{
+			expected:    "a = \nb = x,",
+			description: "it leaves null empty",
+			input: map[string]interface{}{
+				"a": nil,
+				"b": []interface{}{"x", nil},
+			},
+		},
+	} {
+		actual := helpers.ToINI(test.input)
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestINI__roundTrip(t *testing.T) {
+	helpers := New(template.New("envp"))
+	expected := map[string]interface{}{
+		"a": "x\ny = z",
+		"b": "# \"quoted\"",
+		"c": " padded ",
+	}
+
+	actual := helpers.FromINI(helpers.ToINI(expected))
+	assert.Equal(t, expected, actual)
+}
+
+func TestNormalize(t *testing.T) {
+	actual := Normalize(map[string]interface{}{
+		"a": map[string]int{"b": 1},
+		"c": []string{"d"},
+		"e": map[int][]int{1: []int{2}},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"b": 1},
+		"c": []interface{}{"d"},
+		"e": map[string]interface{}{"1": []interface{}{2}},
+	}, actual)
+}
@@ -0,0 +1,188 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	apr1Magic    = "$apr1$"
+	apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	scramIter    = 4096
+	saltSize     = 16
+)
+
+// Bcrypt hashes a password with bcrypt at cost
+func (h *Helpers) Bcrypt(s string, cost int) string {
+	b, err := bcrypt.GenerateFromPassword([]byte(s), cost)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return string(b)
+}
+
+// Argon2id hashes a password with argon2id, time is
+// the number of passes, memory is in KiB, and threads is
+// the parallelism, the output is the standard PHC string.
+func (h *Helpers) Argon2id(s string, time, memory, threads int) string {
+	salt := randomBytes(saltSize)
+	if time < 1 || memory < 1 || threads < 1 || threads > 255 {
+		logrus.Fatalln("argon2id needs a time, memory, and threads (1-255)")
+	}
+
+	enc := base64.RawStdEncoding
+	key := argon2.IDKey([]byte(s), salt, uint32(time), uint32(memory), uint8(threads), 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version,
+		memory, time, threads, enc.EncodeToString(salt),
+		enc.EncodeToString(key))
+}
+
+// apr1 is Apache's variant of md5-crypt, it's old,
+// and weak, but some (embedded) servers only know it.
+func apr1(pw, salt string) string {
+	d := md5.New()
+	d.Write([]byte(pw + apr1Magic + salt))
+	mixin := md5.Sum([]byte(pw + salt + pw))
+	for i := len(pw); i > 0; i -= 16 {
+		n := i
+		if n > 16 {
+			n = 16
+		}
+
+		d.Write(mixin[:n])
+	}
+
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			d.Write([]byte{0})
+		} else {
+			d.Write([]byte(pw[:1]))
+		}
+	}
+
+	final := d.Sum(nil)
+	for i := 0; i < 1000; i++ {
+		d := md5.New()
+		if i&1 != 0 {
+			d.Write([]byte(pw))
+		} else {
+			d.Write(final)
+		}
+
+		if i%3 != 0 {
+			d.Write([]byte(salt))
+		}
+
+		if i%7 != 0 {
+			d.Write([]byte(pw))
+		}
+
+		if i&1 != 0 {
+			d.Write(final)
+		} else {
+			d.Write([]byte(pw))
+		}
+
+		final = d.Sum(nil)
+	}
+
+	var out strings.Builder
+	encode := func(v uint, n int) {
+		for ; n > 0; n-- {
+			out.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+
+	for _, g := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		encode(uint(final[g[0]])<<16|uint(final[g[1]])<<8|uint(final[g[2]]), 4)
+	}
+
+	encode(uint(final[11]), 2)
+	return apr1Magic + salt + "$" + out.String()
+}
+
+// Htpasswd builds a line for an htpasswd file, the
+// algorithm is bcrypt by default, but you can also ask
+// for "sha", or "apr1" if your server is old, and sad.
+func (h *Helpers) Htpasswd(user, pass string, algo ...string) string {
+	a := "bcrypt"
+	if len(algo) > 0 {
+		a = algo[0]
+	}
+
+	var hash string
+	switch a {
+	case "bcrypt":
+		hash = h.Bcrypt(pass, bcrypt.DefaultCost)
+	case "sha":
+		sum := sha1.Sum([]byte(pass))
+		hash = "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+	case "apr1":
+		salt := randomRunes(8, []rune(apr1Alphabet))
+		hash = apr1(pass, string(salt))
+	default:
+		logrus.Fatalf("unknown htpasswd algorithm %s", a)
+	}
+
+	return user + ":" + hash
+}
+
+// scram builds a PostgreSQL SCRAM-SHA-256 verifier
+func scram(pass string, salt []byte, iter int) string {
+	mac := func(key []byte, s string) []byte {
+		m := hmac.New(sha256.New, key)
+		m.Write([]byte(s))
+		return m.Sum(nil)
+	}
+
+	enc := base64.StdEncoding
+	salted := pbkdf2.Key([]byte(pass), salt, iter, sha256.Size, sha256.New)
+	stored := sha256.Sum256(mac(salted, "Client Key"))
+	server := mac(salted, "Server Key")
+	return fmt.Sprintf("SCRAM-SHA-256$%d:%s$%s:%s", iter,
+		enc.EncodeToString(salt), enc.EncodeToString(stored[:]),
+		enc.EncodeToString(server))
+}
+
+// PostgresScram hashes a password the way PostgreSQL
+// stores it for scram-sha-256, iterations default to 4096
+func (h *Helpers) PostgresScram(pass string, iter ...int) string {
+	i := scramIter
+	if len(iter) > 0 {
+		i = iter[0]
+	}
+
+	return scram(pass, randomBytes(saltSize), i)
+}
+
+// PostgresMd5 hashes a password the way PostgreSQL
+// stores it for md5 auth, it's salted with the user.
+func (h *Helpers) PostgresMd5(user, pass string) string {
+	sum := md5.Sum([]byte(pass + user))
+	return "md5" + hex.EncodeToString(sum[:])
+}
+
+// MysqlNativePassword hashes a password the way that
+// MySQL stores it for mysql_native_password auth.
+func (h *Helpers) MysqlNativePassword(pass string) string {
+	first := sha1.Sum([]byte(pass))
+	second := sha1.Sum(first[:])
+	return "*" + strings.ToUpper(hex.EncodeToString(second[:]))
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcrypt(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.Bcrypt("secret", 4)
+	assert.True(t, strings.HasPrefix(actual, "$2a$04$"))
+	assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(actual),
+		[]byte("secret")))
+}
+
+func TestArgon2id(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.Argon2id("secret", 1, 1024, 1)
+	parts := strings.Split(actual, "$")
+	if assert.Len(t, parts, 6) {
+		assert.Equal(t, "argon2id", parts[1])
+		assert.Equal(t, "m=1024,t=1,p=1", parts[3])
+
+		enc := base64.RawStdEncoding
+		salt, _ := enc.DecodeString(parts[4])
+		expected := argon2.IDKey([]byte("secret"), salt, 1, 1024, 1, 32)
+		assert.Equal(t, enc.EncodeToString(expected),
+			parts[5])
+	}
+}
+
+func TestHtpasswd(t *testing.T) {
+	helpers := New(template.New("envp"))
+	type TestStruct struct {
+		expected    string
+		description string
+		algo        []string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "user:$2a$",
+			description: "it's bcrypt by default",
+		},
+		TestStruct{
+			expected:    "user:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=",
+			description: "it works with sha",
+			algo:        []string{"sha"},
+		},
+		TestStruct{
+			expected:    "user:$apr1$",
+			description: "it works with apr1",
+			algo:        []string{"apr1"},
+		},
+	} {
+		actual := helpers.Htpasswd("user", "secret", test.algo...)
+		assert.True(t, strings.HasPrefix(actual, test.expected),
+			test.description)
+	}
+}
+
+func TestApr1(t *testing.T) {
+	expected := "$apr1$abcdefgh$FBwExRW4dCc8aL.OvjpIE1"
+	assert.Equal(t, expected, apr1("password",
+		"abcdefgh"))
+}
+
+func TestScram(t *testing.T) {
+	expected := "SCRAM-SHA-256$4096:MDEyMzQ1Njc4OWFiY2RlZg==$" +
+		"bpSY5Ze9NUH+I35LC3gVq+DpBfK46iXBxvhAKqVu9pE=:" +
+		"VpYlBuxyzeCI1KnctrefdljpB1mk3Gp7sBI/t11+NkQ="
+
+	actual := scram("secret", []byte("0123456789abcdef"), 4096)
+	assert.Equal(t, expected, actual)
+
+	helpers := New(template.New("envp"))
+	actual = helpers.PostgresScram("secret", 10)
+	assert.True(t, strings.HasPrefix(actual,
+		"SCRAM-SHA-256$10:"))
+}
+
+func TestPostgresMd5(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.PostgresMd5("postgres", "secret")
+	assert.Equal(t, "md553f48b7c4b76a86ce72276c5755f217d",
+		actual)
+}
+
+func TestMysqlNativePassword(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.MysqlNativePassword("secret")
+	assert.Equal(t, "*14E65567ABDB5135D0CFD9A70B3032C179A49EE7",
+		actual)
+}
@@ -5,16 +5,15 @@
 package helpers
 
 import (
-	"crypto/rand"
 	"fmt"
-	"io"
-	"math/big"
+	"net"
 	"os"
 	"regexp"
-	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/envygeeks/envp/crypt"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,6 +22,51 @@ import (
 // Some don't use it at all.
 type Helpers struct {
 	template *template.Template
+
+	// ConsulAddr, and EtcdAddr are the addresses
+	// the kv helpers talk to, when empty we fall back
+	// to the env, and then to the local agent.
+	ConsulAddr string
+	EtcdAddr   string
+
+	// StateFile is where persistent secrets
+	// are kept, see state.Path for the fallback.
+	StateFile string
+
+	// AllowRead are the roots that the file
+	// helpers can read from, nothing if it's empty.
+	AllowRead []string
+
+	// AllowWrite are the roots that helpers (like
+	// a cert's .Save) can write to, nothing if it's empty.
+	AllowWrite []string
+
+	// Version is the version of envp, so that
+	// templates can requireEnvp a newer version.
+	Version string
+
+	// DNSTimeout is how long a lookup can take,
+	// when it's zero it's 5s, like most resolvers.
+	DNSTimeout time.Duration
+
+	// Time is the time of the render, when it's
+	// zero we use $SOURCE_DATE_EPOCH, or the clock.
+	Time time.Time
+
+	// Render runs a partial by name, when it's nil
+	// we run it here, html/template swaps it out so the
+	// partials are escaped in context, like the rest.
+	Render func(name string, data interface{}) (string, error)
+
+	// Parsed is called after tpl parses a template
+	// while running, so that it can be escaped too.
+	Parsed func()
+
+	tpls     int
+	keys     *crypt.Keys
+	regexps  map[string]*regexp.Regexp
+	lookups  map[string]interface{}
+	resolver *net.Resolver
 }
 
 // EnvExists allows you to check if a var exists
@@ -45,20 +89,20 @@ func (h *Helpers) Env(s string) string {
 	return ""
 }
 
-// BoolEnv allows you to pull out a var as bool
+// BoolEnv pulls out a var as a bool, like toBool
+// it's false if it's not set, or isn't a bool
 func (h *Helpers) BoolEnv(s string) bool {
-	s = strings.ToUpper(s)
-	if v, ok := os.LookupEnv(s); ok {
-		obool, err := strconv.ParseBool(v)
-		if err != nil {
-			logrus.Errorln(err)
-			return false
-		}
+	return h.ToBool(h.Env(s))
+}
 
-		return obool
+// MustBoolEnv pulls out a var as a bool, like
+// mustToBool, it's an error if it's set, and not a bool
+func (h *Helpers) MustBoolEnv(s string) (bool, error) {
+	if !h.EnvExists(s) {
+		return false, nil
 	}
 
-	return false
+	return h.MustToBool(h.Env(s))
 }
 
 // AddSpace adds a space to the beginning of a string
@@ -69,6 +113,7 @@ func (h *Helpers) AddSpace(s string, n int) string {
 }
 
 const (
+	tplName         = "_tpl"
 	indentRegex     = `(?m)^[ \t]{%d}`
 	stripEdgesRegex = `(?m)\A[ \t]*$[\r\n]*|[\r\n]+[ \t]*\z`
 	stripEmptyRegex = `(?m)^[ \t]+$`
@@ -122,16 +167,44 @@ func (h *Helpers) Strip(s string) string {
 	return s
 }
 
-// TemplateString pulls a template as a string
-func (h *Helpers) TemplateString(s string) string {
+// dataOf gives you the data that was passed to a
+// template helper, it's optional so it's variadic.
+func dataOf(data []interface{}) interface{} {
+	switch len(data) {
+	case 0:
+		return nil
+	case 1:
+		return data[0]
+	}
+
+	logrus.Fatalf("expected one data argument, got %d", len(data))
+	return nil
+}
+
+// execute runs a template with the data
+func execute(template *template.Template, data interface{}) string {
+	var str strings.Builder
+	if err := template.Execute(&str, data); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	out := str.String()
+	return out
+}
+
+// TemplateString pulls a template as a string, you
+// can pass it data, so that it's available as the dot.
+func (h *Helpers) TemplateString(s string, data ...interface{}) string {
 	if template := h.template.Lookup(s); template != nil {
-		var str strings.Builder
+		if h.Render == nil {
+			return execute(template, dataOf(data))
+		}
 
-		if err := template.Execute(&str, h.template); err != nil {
+		out, err := h.Render(s, dataOf(data))
+		if err != nil {
 			logrus.Fatalln(err)
 		}
 
-		out := str.String()
 		return out
 	}
 
@@ -140,17 +213,41 @@ func (h *Helpers) TemplateString(s string) string {
 	return ""
 }
 
+// Include runs a template with the data, so
+// that you can pass parameters into partials.
+func (h *Helpers) Include(s string, data interface{}) string {
+	return h.TemplateString(s, data)
+}
+
+// Tpl runs a string as a template with the data, it
+// can use all of the helpers, and templates you have,
+// each call gets it's own name, so they can be nested.
+func (h *Helpers) Tpl(s string, data ...interface{}) string {
+	h.tpls++
+	name := fmt.Sprintf("%s%d", tplName, h.tpls)
+	template, err := h.template.New(name).Parse(s)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if h.Parsed != nil {
+		h.Parsed()
+	}
+
+	return execute(template, dataOf(data))
+}
+
 // IndentedTemplate indents a template.
-func (h *Helpers) IndentedTemplate(s string, size uint) string {
-	s = h.TemplateString(s)
+func (h *Helpers) IndentedTemplate(s string, size uint, data ...interface{}) string {
+	s = h.TemplateString(s, data...)
 	s = h.Indent(s, size)
 	return s
 }
 
 // TemplateWithNewLine returns a template with
 // a newline if the template returned is not empty
-func (h *Helpers) TemplateWithNewLine(s string) string {
-	s = h.FixIndentedTemplate(s)
+func (h *Helpers) TemplateWithNewLine(s string, data ...interface{}) string {
+	s = h.FixIndentedTemplate(s, data...)
 	if s != "" {
 		return "\n" + s
 	}
@@ -159,8 +256,8 @@ func (h *Helpers) TemplateWithNewLine(s string) string {
 }
 
 // IndentedTemplateWithNewLine adds a newline, and indents
-func (h *Helpers) IndentedTemplateWithNewLine(s string, size uint) string {
-	s = h.IndentedTemplate(s, size)
+func (h *Helpers) IndentedTemplateWithNewLine(s string, size uint, data ...interface{}) string {
+	s = h.IndentedTemplate(s, size, data...)
 	if s != "" {
 		return "\n" + s
 	}
@@ -169,15 +266,15 @@ func (h *Helpers) IndentedTemplateWithNewLine(s string, size uint) string {
 }
 
 // StrippedTemplate trims empty lines, and edges.
-func (h *Helpers) StrippedTemplate(s string) string {
-	s = h.TemplateString(s)
+func (h *Helpers) StrippedTemplate(s string, data ...interface{}) string {
+	s = h.TemplateString(s, data...)
 	s = h.Strip(s)
 	return s
 }
 
 // FixIndentedTemplate strips the indentation to the edge
-func (h *Helpers) FixIndentedTemplate(s string) string {
-	s = h.TemplateString(s)
+func (h *Helpers) FixIndentedTemplate(s string, data ...interface{}) string {
+	s = h.TemplateString(s, data...)
 	s = h.FixIndentation(s)
 	return s
 }
@@ -192,40 +289,6 @@ func (h *Helpers) TemplateExists(s string) bool {
 	return false
 }
 
-var (
-	letters    = []rune("01234567890abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
-	lettersLen = int64(len(letters))
-)
-
-func rngCheck() {
-	buf := make([]byte, 1)
-	_, err := io.ReadFull(rand.Reader, buf)
-	if err != nil {
-		logrus.Fatalln(err)
-	}
-}
-
-// RandomPassword generates a password with
-// cryptographically derived random numbers
-func (h *Helpers) RandomPassword(size uint) string {
-	rngCheck()
-
-	rune := make([]rune, size)
-	for i := range rune {
-		n, err := rand.Int(rand.Reader, big.NewInt(lettersLen))
-		if err != nil {
-			logrus.Fatalln(err)
-		}
-
-		idx := n.Int64()
-		letter := letters[idx]
-		rune[i] = letter
-	}
-
-	out := string(rune)
-	return out
-}
-
 // New creates a new Funcs, and registers them
 func New(t *template.Template) *Helpers {
 	helpers := (&Helpers{template: t}).Register()
@@ -235,13 +298,187 @@ func New(t *template.Template) *Helpers {
 // Register registers the funcs
 func (h *Helpers) Register() *Helpers {
 	logrus.Debug("registering all the helpers")
-	h.template.Funcs(template.FuncMap{
+	h.template.Funcs(h.FuncMap())
+	return h
+}
+
+// FuncMap returns all of the funcs, so that they
+// can be handed to other templates (html/template)
+func (h *Helpers) FuncMap() template.FuncMap {
+	funcs := template.FuncMap{
 		"split":                       strings.Split,
 		"chomp":                       strings.Trim,
 		"indent":                      h.Indent,
 		"addSpace":                    h.AddSpace,
 		"randomPassword":              h.RandomPassword,
+		"persistentPassword":          h.PersistentPassword,
+		"randomPasswordWith":          h.RandomPasswordWith,
+		"randomBase64":                h.RandomBase64,
+		"randomString":                h.RandomString,
+		"randomHex":                   h.RandomHex,
+		"randomInt":                   h.RandomInt,
+		"uuidv7":                      h.UUIDv7,
+		"uuid":                        h.UUID,
+		"mysqlNativePassword":         h.MysqlNativePassword,
+		"postgresScram":               h.PostgresScram,
+		"postgresMd5":                 h.PostgresMd5,
+		"htpasswd":                    h.Htpasswd,
+		"argon2id":                    h.Argon2id,
+		"bcrypt":                      h.Bcrypt,
+		"selfSignedCert":              h.SelfSignedCert,
+		"generateCA":                  h.GenerateCA,
+		"signCert":                    h.SignCert,
+		"wireguardKeypair":            h.WireguardKeypair,
+		"sshKeypair":                  h.SSHKeypair,
+		"hmacSha256":                  h.HmacSha256,
+		"jwtSign":                     h.JWTSign,
+		"b64urlenc":                   h.B64urlenc,
+		"b64enc":                      h.B64enc,
+		"b64dec":                      h.B64dec,
+		"b32enc":                      h.B32enc,
+		"hexenc":                      h.Hexenc,
+		"hexdec":                      h.Hexdec,
+		"sha512":                      h.Sha512,
+		"sha256":                      h.Sha256,
+		"sha1":                        h.Sha1,
+		"crc32":                       h.Crc32,
+		"md5":                         h.Md5,
+		"toPrettyJson":                h.ToPrettyJSON,
+		"toProperties":                h.ToProperties,
+		"fromJson":                    h.FromJSON,
+		"fromYaml":                    h.FromYAML,
+		"fromToml":                    h.FromTOML,
+		"fromIni":                     h.FromINI,
+		"toJson":                      h.ToJSON,
+		"toYaml":                      h.ToYAML,
+		"toToml":                      h.ToTOML,
+		"toIni":                       h.ToINI,
+		"quoteShell":                  h.QuoteShell,
+		"quoteJson":                   h.QuoteJSON,
+		"quoteYaml":                   h.QuoteYAML,
+		"quoteToml":                   h.QuoteTOML,
+		"quoteXml":                    h.QuoteXML,
+		"quoteIni":                    h.QuoteINI,
+		"quoteNginx":                  h.QuoteNginx,
+		"quoteSql":                    h.QuoteSQL,
+		"quoteRegex":                  h.QuoteRegex,
+		"interfaceAddrs":              h.InterfaceAddrs,
+		"lookupCNAME":                 h.LookupCNAME,
+		"lookupHost":                  h.LookupHost,
+		"resolvConf":                  h.ResolvConf,
+		"lookupSRV":                   h.LookupSRV,
+		"lookupIP":                    h.LookupIP,
+		"memoryLimit":                 h.MemoryLimit,
+		"lookupGroup":                 h.LookupGroup,
+		"lookupUser":                  h.LookupUser,
+		"username":                    h.Username,
+		"hostname":                    h.Hostname,
+		"numCPU":                      h.NumCPU,
+		"host":                        h.Host,
+		"fqdn":                        h.FQDN,
+		"uid":                         h.UID,
+		"gid":                         h.GID,
+		"cidrContains":                h.CidrContains,
+		"cidrNetmask":                 h.CidrNetmask,
+		"ipIsPrivate":                 h.IPIsPrivate,
+		"cidrSubnet":                  h.CidrSubnet,
+		"primaryIP":                   h.PrimaryIP,
+		"ipVersion":                   h.IPVersion,
+		"cidrHost":                    h.CidrHost,
+		"fileExists":                  h.FileExists,
+		"readLines":                   h.ReadLines,
+		"readFile":                    h.ReadFile,
+		"basename":                    h.Basename,
+		"readDir":                     h.ReadDir,
+		"dirname":                     h.Dirname,
+		"isDir":                       h.IsDir,
+		"glob":                        h.Glob,
+		"ext":                         h.Ext,
+		"abs":                         h.Abs,
+		"semverCompare":               h.SemverCompare,
+		"requireEnvp":                 h.RequireEnvp,
+		"semver":                      h.Semver,
+		"durationRound":               h.DurationRound,
+		"inTimezone":                  h.InTimezone,
+		"dateModify":                  h.DateModify,
+		"unixEpoch":                   h.UnixEpoch,
+		"duration":                    h.Duration,
+		"toDate":                      h.ToDate,
+		"date":                        h.Date,
+		"now":                         h.Now,
+		"mustBoolEnv":                 h.MustBoolEnv,
+		"mustToFloat":                 h.MustToFloat,
+		"mustToBool":                  h.MustToBool,
+		"mustToInt":                   h.MustToInt,
+		"toStrings":                   h.ToStrings,
+		"toString":                    h.ToString,
+		"toFloat":                     h.ToFloat,
+		"toBool":                      h.ToBool,
+		"toInt":                       h.ToInt,
+		"kindOf":                      h.KindOf,
+		"typeOf":                      h.TypeOf,
+		"kindIs":                      h.KindIs,
+		"formatBytes":                 h.FormatBytes,
+		"parseBytes":                  h.ParseBytes,
+		"percent":                     h.Percent,
+		"floor":                       h.Floor,
+		"round":                       h.Round,
+		"ceil":                        h.Ceil,
+		"add":                         h.Add,
+		"sub":                         h.Sub,
+		"mul":                         h.Mul,
+		"div":                         h.Div,
+		"mod":                         h.Mod,
+		"max":                         h.Max,
+		"min":                         h.Min,
+		"regexReplaceAll":             h.RegexReplaceAll,
+		"regexFindAll":                h.RegexFindAll,
+		"regexCapture":                h.RegexCapture,
+		"regexMatch":                  h.RegexMatch,
+		"regexSplit":                  h.RegexSplit,
+		"regexFind":                   h.RegexFind,
+		"trimPrefix":                  h.TrimPrefix,
+		"trimSuffix":                  h.TrimSuffix,
+		"hasPrefix":                   h.HasPrefix,
+		"hasSuffix":                   h.HasSuffix,
+		"camelcase":                   h.Camelcase,
+		"snakecase":                   h.Snakecase,
+		"kebabcase":                   h.Kebabcase,
+		"contains":                    h.Contains,
+		"truncate":                    h.Truncate,
+		"replace":                     h.Replace,
+		"nospace":                     h.Nospace,
+		"splitN":                      h.SplitN,
+		"substr":                      h.Substr,
+		"repeat":                      h.Repeat,
+		"upper":                       h.Upper,
+		"lower":                       h.Lower,
+		"title":                       h.Title,
+		"trim":                        h.Trim,
+		"join":                        h.Join,
+		"wrap":                        h.Wrap,
+		"mergeOverwrite":              h.MergeOverwrite,
+		"merge":                       h.Merge,
+		"sortAlpha":                   h.SortAlpha,
+		"prepend":                     h.Prepend,
+		"append":                      h.Append,
+		"hasKey":                      h.HasKey,
+		"values":                      h.Values,
+		"unset":                       h.Unset,
+		"keys":                        h.Keys,
+		"pick":                        h.Pick,
+		"omit":                        h.Omit,
+		"uniq":                        h.Uniq,
+		"first":                       h.First,
+		"last":                        h.Last,
+		"rest":                        h.Rest,
+		"reverse":                     h.Reverse,
+		"dict":                        h.Dict,
+		"list":                        h.List,
+		"set":                         h.Set,
 		"templateString":              h.TemplateString,
+		"include":                     h.Include,
+		"tpl":                         h.Tpl,
 		"strippedTemplate":            h.StrippedTemplate,
 		"fixIndentedTemplate":         h.FixIndentedTemplate,
 		"indentedTemplateWithNewline": h.IndentedTemplateWithNewLine,
@@ -250,10 +487,18 @@ func (h *Helpers) Register() *Helpers {
 		"templateExists":              h.TemplateExists,
 		"fixIndentation":              h.FixIndentation,
 		"envExists":                   h.EnvExists,
+		"decrypt":                     h.Decrypt,
+		"kvTree":                      h.KvTree,
+		"kv":                          h.Kv,
 		"boolEnv":                     h.BoolEnv,
 		"strip":                       h.Strip,
 		"env":                         h.Env,
-	})
+		"raw":                         h.Raw,
+	}
 
-	return h
+	for k, v := range h.escapers() {
+		funcs[k] = v
+	}
+
+	return funcs
 }
@@ -7,12 +7,41 @@ package helpers
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"text/template"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
+// assertFatal checks that fn stops with logrus.Fatal,
+// the exit is swapped for a panic so the tests go on.
+func assertFatal(t *testing.T, fn func(), description string) {
+	logger := logrus.StandardLogger()
+	exit := logger.ExitFunc
+	logger.ExitFunc = func(int) { panic("fatal") }
+	defer func() { logger.ExitFunc = exit }()
+	assert.PanicsWithValue(t, "fatal", fn, description)
+}
+
+// assertRender renders input with a new set of helpers,
+// setup can change them first, and checks the output.
+func assertRender(t *testing.T, input, expected, description string, setup ...func(h *Helpers)) {
+	tpl := template.New("envp")
+	h := New(tpl)
+	for _, fn := range setup {
+		fn(h)
+	}
+
+	var out strings.Builder
+	template.Must(tpl.Parse(input))
+	if assert.Nil(t, tpl.Execute(&out, nil), description) {
+		assert.Equal(t, expected, out.String(),
+			description)
+	}
+}
+
 func TestEnvExists(t *testing.T) {
 	os.Setenv("BLANK", "")
 	type TestStruct struct {
@@ -229,6 +258,49 @@ func TestTemplateString(t *testing.T) {
 	}
 }
 
+func TestInclude(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "hello world",
+			description: "include passes the data",
+			input:       `{{ define "p" }}hello {{ .name }}{{ end }}{{ include "p" (dict "name" "world") }}`,
+		},
+		TestStruct{
+			expected:    "HELLO",
+			description: "include can be piped",
+			input:       `{{ define "p" }}{{ . }}{{ end }}{{ include "p" "HELLO" | quoteRegex }}`,
+		},
+		TestStruct{
+			expected:    "a: 1",
+			description: "templateString takes data",
+			input:       `{{ define "p" }}a: {{ .a }}{{ end }}{{ templateString "p" (dict "a" 1) }}`,
+		},
+		TestStruct{
+			expected:    "hello world",
+			description: "tpl renders a string with the data",
+			input:       `{{ tpl "hello {{ .name }}" (dict "name" "world") }}`,
+		},
+		TestStruct{
+			expected:    "x-y",
+			description: "tpl can include templates",
+			input:       `{{ define "p" }}{{ .a }}-{{ .b }}{{ end }}{{ tpl "{{ include \"p\" . }}" (dict "a" "x" "b" "y") }}`,
+		},
+		TestStruct{
+			expected:    "\n    a: 1\n    b: 2",
+			description: "the indentation helpers take data",
+			input:       "{{ define \"p\" }}\n  a: {{ .a }}\n  b: {{ .b }}\n{{ end }}{{ indentedTemplateWithNewline \"p\" 4 (dict \"a\" 1 \"b\" 2) }}",
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
+
 func TestStrippedTemplate(t *testing.T) {
 	tpldef := "{{ define \"hello\" }}%s{{ end }}"
 	type TestStruct struct {
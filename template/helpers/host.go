@@ -0,0 +1,369 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"bufio"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// They're vars so that the tests
+// can point them at fixtures.
+var (
+	cgroupRoot = "/sys/fs/cgroup"
+	cgroupFile = "/proc/self/cgroup"
+	passwdFile = "/etc/passwd"
+	groupFile  = "/etc/group"
+)
+
+// v1 says "no limit" with a huge number, and
+// not max, anything over this is no limit.
+const noMemoryLimit = 1 << 62
+
+/**
+ * Host is .Host in templates, it's methods so
+ * that we only look up what the template uses,
+ * like .Host.NumCPU, or .Host.FQDN
+ */
+type Host struct {
+	h *Helpers
+}
+
+// Hostname gives you the hostname
+func (h *Host) Hostname() string { return h.h.Hostname() }
+
+// FQDN gives you the fully qualified hostname
+func (h *Host) FQDN() string { return h.h.FQDN() }
+
+// OS gives you the OS, like linux
+func (h *Host) OS() string { return runtime.GOOS }
+
+// Arch gives you the arch, like amd64, or arm64
+func (h *Host) Arch() string { return runtime.GOARCH }
+
+// NumCPU gives you the CPUs, see numCPU
+func (h *Host) NumCPU() int { return h.h.NumCPU() }
+
+// MemoryLimit gives you the limit, see memoryLimit
+func (h *Host) MemoryLimit() int64 { return h.h.MemoryLimit() }
+
+// UID gives you the uid that we run as
+func (h *Host) UID() int { return h.h.UID() }
+
+// GID gives you the gid that we run as
+func (h *Host) GID() int { return h.h.GID() }
+
+// Username gives you the user that we run as
+func (h *Host) Username() string { return h.h.Username() }
+
+// Host gives you the host, it's .Host too
+func (h *Helpers) Host() *Host {
+	return &Host{h: h}
+}
+
+// Hostname gives you the hostname
+func (h *Helpers) Hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return name
+}
+
+// FQDN gives you the fully qualified hostname, we
+// look up the host, and then look up the name of it's
+// addresses, if that doesn't work it's the hostname.
+func (h *Helpers) FQDN() string {
+	name := h.Hostname()
+	if strings.Contains(name, ".") {
+		return name
+	}
+
+	ips, err := net.LookupIP(name)
+	if err != nil {
+		logrus.Debugln(err)
+		return name
+	}
+
+	for _, ip := range ips {
+		names, err := net.LookupAddr(ip.String())
+		if err != nil {
+			continue
+		}
+
+		for _, n := range names {
+			n = strings.TrimSuffix(n, ".")
+			if strings.HasPrefix(n, name+".") {
+				return n
+			}
+		}
+	}
+
+	return name
+}
+
+// cgroupV2 tells you if the unified hierarchy is there
+func cgroupV2() bool {
+	_, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers"))
+	return err == nil
+}
+
+// cgroupDirs gives you the dirs of our cgroup for the
+// controller, from ours up to the root, because a limit
+// can be set on any of them, and the lowest one wins.
+func cgroupDirs(controller string) []string {
+	v2, base, path := cgroupV2(), "", "/"
+	if b, err := ioutil.ReadFile(cgroupFile); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			parts := strings.SplitN(line, ":", 3)
+			if len(parts) != 3 {
+				continue
+			}
+
+			if v2 && parts[0] == "0" && parts[1] == "" {
+				path = parts[2]
+				break
+			}
+
+			for _, c := range strings.Split(parts[1], ",") {
+				if !v2 && c == controller {
+					base, path = parts[1], parts[2]
+				}
+			}
+		}
+	}
+
+	if !v2 && base == "" {
+		base = controller
+	}
+
+	// In a container the path is the path on the
+	// host, but we only see our own cgroup, at the root.
+	base = filepath.Join(cgroupRoot, base)
+	if _, err := os.Stat(filepath.Join(base, path)); err != nil {
+		path = "/"
+	}
+
+	dirs := []string{}
+	for {
+		dirs = append(dirs, filepath.Join(base, path))
+		if path == "/" || path == "." {
+			break
+		}
+
+		path = filepath.Dir(path)
+	}
+
+	return dirs
+}
+
+// cgroupInt reads a number out of a cgroup file, a
+// "max", "-1", or a file that isn't there isn't a limit.
+func cgroupInt(dir, file string) (int64, bool) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return 0, false
+	}
+
+	i, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	return i, err == nil && i > 0
+}
+
+// cpuLimit gives you the CPU quota, as CPUs (it can
+// be a fraction, like 1.5) if there is one on the cgroup.
+func cpuLimit() (float64, bool) {
+	limit, ok := math.Inf(1), false
+	for _, dir := range cgroupDirs("cpu") {
+		var quota, period int64
+		if cgroupV2() {
+			b, err := ioutil.ReadFile(filepath.Join(dir, "cpu.max"))
+			fields := strings.Fields(string(b))
+			if err != nil || len(fields) != 2 || fields[0] == "max" {
+				continue
+			}
+
+			quota, _ = strconv.ParseInt(fields[0], 10, 64)
+			period, _ = strconv.ParseInt(fields[1], 10, 64)
+		} else {
+			var qok, pok bool
+			quota, qok = cgroupInt(dir, "cpu.cfs_quota_us")
+			period, pok = cgroupInt(dir, "cpu.cfs_period_us")
+			if !qok || !pok {
+				continue
+			}
+		}
+
+		if quota > 0 && period > 0 {
+			limit = math.Min(limit, float64(quota)/float64(period))
+			ok = true
+		}
+	}
+
+	return limit, ok
+}
+
+// NumCPU gives you the CPUs that we can use, if
+// there's a cgroup quota (like docker --cpus) it's the
+// quota, rounded up, so 1.5 is 2, it's never under 1.
+func (h *Helpers) NumCPU() int {
+	n := runtime.NumCPU()
+	if limit, ok := cpuLimit(); ok && limit < float64(n) {
+		n = int(math.Ceil(limit))
+	}
+
+	if n < 1 {
+		n = 1
+	}
+
+	return n
+}
+
+// MemoryLimit gives you the cgroup memory limit, in
+// bytes (like docker --memory) it's 0 if there's none.
+func (h *Helpers) MemoryLimit() int64 {
+	file := "memory.limit_in_bytes"
+	if cgroupV2() {
+		file = "memory.max"
+	}
+
+	var limit int64
+	for _, dir := range cgroupDirs("memory") {
+		if i, ok := cgroupInt(dir, file); ok && i < noMemoryLimit {
+			if limit == 0 || i < limit {
+				limit = i
+			}
+		}
+	}
+
+	return limit
+}
+
+// UID gives you the uid that we run as
+func (h *Helpers) UID() int {
+	return os.Getuid()
+}
+
+// GID gives you the gid that we run as
+func (h *Helpers) GID() int {
+	return os.Getgid()
+}
+
+// Username gives you the user that we run as, out
+// of /etc/passwd, or $USER, or the uid if there's none.
+func (h *Helpers) Username() string {
+	if u := h.LookupUser(h.UID()); u != nil {
+		return u.Username
+	}
+
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+
+	return strconv.Itoa(h.UID())
+}
+
+/**
+ * User is a user out of /etc/passwd
+ */
+type User struct {
+	Username string
+	Name     string
+	HomeDir  string
+	Shell    string
+	UID      int
+	GID      int
+}
+
+/**
+ * Group is a group out of /etc/group
+ */
+type Group struct {
+	Name    string
+	Members []string
+	GID     int
+}
+
+// readColon reads a colon separated file, like
+// /etc/passwd, or /etc/group, comments are skipped.
+func readColon(path string, size int) [][]string {
+	f, err := os.Open(path)
+	if err != nil {
+		logrus.Debugln(err)
+		return nil
+	}
+
+	defer f.Close()
+	out := [][]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if parts := strings.Split(line, ":"); len(parts) >= size {
+			out = append(out, parts)
+		}
+	}
+
+	return out
+}
+
+// LookupUser looks up a user by it's name, or it's
+// uid, you get nil if there's no user, so use with.
+func (h *Helpers) LookupUser(v interface{}) *User {
+	s := h.ToString(v)
+	for _, parts := range readColon(passwdFile, 7) {
+		if parts[0] == s || parts[2] == s {
+			uid, _ := strconv.Atoi(parts[2])
+			gid, _ := strconv.Atoi(parts[3])
+			return &User{
+				Name:     strings.SplitN(parts[4], ",", 2)[0],
+				Username: parts[0],
+				HomeDir:  parts[5],
+				Shell:    parts[6],
+				UID:      uid,
+				GID:      gid,
+			}
+		}
+	}
+
+	return nil
+}
+
+// LookupGroup looks up a group by it's name, or it's
+// gid, you get nil if there's no group, so use with.
+func (h *Helpers) LookupGroup(v interface{}) *Group {
+	s := h.ToString(v)
+	for _, parts := range readColon(groupFile, 4) {
+		if parts[0] == s || parts[2] == s {
+			members := []string{}
+			for _, m := range strings.Split(parts[3], ",") {
+				if m = strings.TrimSpace(m); m != "" {
+					members = append(members, m)
+				}
+			}
+
+			gid, _ := strconv.Atoi(parts[2])
+			return &Group{
+				Name:    parts[0],
+				Members: members,
+				GID:     gid,
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtures writes the files into a tmp dir
+func fixtures(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "envp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		ioutil.WriteFile(path, []byte(content), 0644)
+	}
+
+	return dir
+}
+
+// withCgroup points the cgroup vars at a tmp dir
+func withCgroup(t *testing.T, files map[string]string, fn func()) {
+	dir := fixtures(t, files)
+	defer os.RemoveAll(dir)
+
+	oldRoot, oldFile := cgroupRoot, cgroupFile
+	cgroupRoot, cgroupFile = filepath.Join(dir, "cgroup"), filepath.Join(dir, "self")
+	defer func() { cgroupRoot, cgroupFile = oldRoot, oldFile }()
+	fn()
+}
+
+func TestCgroup(t *testing.T) {
+	type TestStruct struct {
+		cpu         int
+		memory      int64
+		description string
+		files       map[string]string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			cpu:         2,
+			memory:      512 << 20,
+			description: "v2 at the root, like in a container",
+			files: map[string]string{
+				"self":                      "0::/\n",
+				"cgroup/cgroup.controllers": "cpu memory\n",
+				"cgroup/cpu.max":            "150000 100000\n",
+				"cgroup/memory.max":         "536870912\n",
+			},
+		},
+		TestStruct{
+			cpu:         1,
+			memory:      256 << 20,
+			description: "v2 takes the lowest limit of the tree",
+			files: map[string]string{
+				"self":                          "0::/app/web\n",
+				"cgroup/cgroup.controllers":     "cpu memory\n",
+				"cgroup/app/cpu.max":            "50000 100000\n",
+				"cgroup/app/memory.max":         "268435456\n",
+				"cgroup/app/web/cpu.max":        "max 100000\n",
+				"cgroup/app/web/memory.max":     "max\n",
+				"cgroup/app/web/cgroup.procs":   "1\n",
+				"cgroup/app/cgroup.controllers": "cpu memory\n",
+			},
+		},
+		TestStruct{
+			cpu:         runtime.NumCPU(),
+			memory:      8 << 30,
+			description: "v2 with a limit over 2 GiB",
+			files: map[string]string{
+				"self":                      "0::/\n",
+				"cgroup/cgroup.controllers": "cpu memory\n",
+				"cgroup/memory.max":         "8589934592\n",
+			},
+		},
+		TestStruct{
+			cpu:         runtime.NumCPU(),
+			memory:      0,
+			description: "v2 without limits",
+			files: map[string]string{
+				"self":                      "0::/\n",
+				"cgroup/cgroup.controllers": "cpu memory\n",
+				"cgroup/cpu.max":            "max 100000\n",
+				"cgroup/memory.max":         "max\n",
+			},
+		},
+		TestStruct{
+			cpu:         1,
+			memory:      1 << 30,
+			description: "v1, with the host path in /proc/self/cgroup",
+			files: map[string]string{
+				"self": "4:memory:/docker/abc\n" +
+					"3:cpu,cpuacct:/docker/abc\n",
+				"cgroup/cpu,cpuacct/cpu.cfs_quota_us":  "100000\n",
+				"cgroup/cpu,cpuacct/cpu.cfs_period_us": "100000\n",
+				"cgroup/memory/memory.limit_in_bytes":  "1073741824\n",
+			},
+		},
+		TestStruct{
+			cpu:         runtime.NumCPU(),
+			memory:      0,
+			description: "v1 without limits",
+			files: map[string]string{
+				"self":                                 "3:cpu,cpuacct:/\n",
+				"cgroup/cpu,cpuacct/cpu.cfs_quota_us":  "-1\n",
+				"cgroup/cpu,cpuacct/cpu.cfs_period_us": "100000\n",
+				"cgroup/memory/memory.limit_in_bytes":  "9223372036854771712\n",
+			},
+		},
+	} {
+		withCgroup(t, test.files, func() {
+			// The quota can't give you more than the host has
+			cpu := test.cpu
+			if n := runtime.NumCPU(); n < cpu {
+				cpu = n
+			}
+
+			h := New(template.New("envp"))
+			assert.Equal(t, cpu, h.NumCPU(), test.description)
+			assert.Equal(t, test.memory, h.MemoryLimit(), test.description)
+		})
+	}
+}
+
+func TestLookupUser(t *testing.T) {
+	dir := fixtures(t, map[string]string{
+		"passwd": "# users\nroot:x:0:0:root:/root:/bin/sh\n" +
+			"nginx:x:101:101:Nginx web server,,,:/var/lib/nginx:/sbin/nologin\n",
+		"group": "root:x:0:\nwww:x:33:nginx, app\n",
+	})
+
+	defer os.RemoveAll(dir)
+	oldPasswd, oldGroup := passwdFile, groupFile
+	passwdFile, groupFile = filepath.Join(dir, "passwd"), filepath.Join(dir, "group")
+	defer func() { passwdFile, groupFile = oldPasswd, oldGroup }()
+
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "nginx 101 101 /var/lib/nginx /sbin/nologin Nginx web server",
+			description: "lookupUser looks up by name",
+			input:       `{{ with lookupUser "nginx" }}{{ .Username }} {{ .UID }} {{ .GID }} {{ .HomeDir }} {{ .Shell }} {{ .Name }}{{ end }}`,
+		},
+		TestStruct{
+			expected:    "root",
+			description: "lookupUser looks up by uid",
+			input:       `{{ (lookupUser 0).Username }}`,
+		},
+		TestStruct{
+			expected:    "none",
+			description: "lookupUser is nil if there's no user",
+			input:       `{{ with lookupUser "nope" }}{{ .Username }}{{ else }}none{{ end }}`,
+		},
+		TestStruct{
+			expected:    "www 33 nginx,app",
+			description: "lookupGroup looks up by name",
+			input:       `{{ with lookupGroup "www" }}{{ .Name }} {{ .GID }} {{ join "," .Members }}{{ end }}`,
+		},
+		TestStruct{
+			expected:    "root 0",
+			description: "lookupGroup looks up by gid",
+			input:       `{{ with lookupGroup "0" }}{{ .Name }} {{ len .Members }}{{ end }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
+
+func TestHost(t *testing.T) {
+	h := New(template.New("envp"))
+	host, _ := os.Hostname()
+
+	assert.Equal(t, host, h.Host().Hostname())
+	assert.Equal(t, runtime.GOOS, h.Host().OS())
+	assert.Equal(t, runtime.GOARCH, h.Host().Arch())
+	assert.Equal(t, os.Getuid(), h.Host().UID())
+	assert.Equal(t, os.Getgid(), h.Host().GID())
+	assert.NotEmpty(t, h.Host().Username())
+	assert.True(t, h.Host().NumCPU() >= 1)
+}
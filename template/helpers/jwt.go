@@ -0,0 +1,188 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"hash"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	jwtEncoding = base64.RawURLEncoding
+	jwtHashes   = map[string]func() hash.Hash{
+		"256": sha256.New,
+		"384": sha512.New384,
+		"512": sha512.New,
+	}
+
+	// jwtCurves are the curves that each of the
+	// ES algorithms needs, ES512 is P-521, not P-512.
+	jwtCurves = map[string]elliptic.Curve{
+		"ES256": elliptic.P256(),
+		"ES384": elliptic.P384(),
+		"ES512": elliptic.P521(),
+	}
+)
+
+// jwtClaims takes claims as a map (like dict) or
+// as a JSON string, and gives them back as JSON.
+func jwtClaims(claims interface{}) []byte {
+	if s, ok := claims.(string); ok {
+		var out map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			logrus.Fatalln(err)
+		}
+
+		claims = out
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return b
+}
+
+// jwtKey parses a PEM key, if it isn't PEM it's
+// treated as the path to a file that holds the PEM,
+// it's read like readFile, so it has to be allowed.
+func (h *Helpers) jwtKey(key string) crypto.Signer {
+	if !strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN") {
+		key = h.ReadFile(key)
+	}
+
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		logrus.Fatalln("unable to decode the key")
+	}
+
+	var (
+		out interface{}
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		out, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		out, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		out, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return out.(crypto.Signer)
+}
+
+// jwtSignature signs the input with the algorithm
+func (h *Helpers) jwtSignature(in []byte, alg, key string) []byte {
+	if alg == "EdDSA" {
+		signer, ok := h.jwtKey(key).(ed25519.PrivateKey)
+		if !ok {
+			logrus.Fatalln("EdDSA needs an ed25519 key")
+		}
+
+		return ed25519.Sign(signer, in)
+	}
+
+	if len(alg) != 5 || jwtHashes[alg[2:]] == nil {
+		logrus.Fatalf("unsupported jwt algorithm %s", alg)
+	}
+
+	newHash := jwtHashes[alg[2:]]
+	if alg[:2] == "HS" {
+		mac := hmac.New(newHash, []byte(key))
+		mac.Write(in)
+		return mac.Sum(nil)
+	}
+
+	digest := newHash()
+	digest.Write(in)
+	sum := digest.Sum(nil)
+	hashes := map[string]crypto.Hash{
+		"256": crypto.SHA256,
+		"384": crypto.SHA384,
+		"512": crypto.SHA512,
+	}
+
+	switch signer := h.jwtKey(key).(type) {
+	case *rsa.PrivateKey:
+		if alg[:2] != "RS" {
+			break
+		}
+
+		sig, err := rsa.SignPKCS1v15(rand.Reader, signer, hashes[alg[2:]], sum)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		return sig
+	case *ecdsa.PrivateKey:
+		if alg[:2] != "ES" {
+			break
+		}
+
+		// Verifiers reject a signature that's the
+		// wrong size, like ES256 with a P-384 key.
+		if signer.Curve != jwtCurves[alg] {
+			logrus.Fatalf("%s needs a %s key, not %s", alg, jwtCurves[alg].Params().Name,
+				signer.Curve.Params().Name)
+		}
+
+		r, s, err := ecdsa.Sign(rand.Reader, signer, sum)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		// JWS wants r, and s as fixed size big endian.
+		size := (signer.Curve.Params().BitSize + 7) / 8
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+		return sig
+	}
+
+	logrus.Fatalf("the key doesn't match the algorithm %s", alg)
+	return nil
+}
+
+// JWTSign signs claims (a map, or JSON) into a JWT, alg
+// is HS256/384/512, RS256/384/512, ES256/384/512, or EdDSA,
+// HS* keys are secrets, the rest are PEM, or a PEM file.
+func (h *Helpers) JWTSign(claims interface{}, alg, key string) string {
+	header, err := json.Marshal(map[string]string{
+		"alg": alg,
+		"typ": "JWT",
+	})
+
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	in := jwtEncoding.EncodeToString(header) + "." +
+		jwtEncoding.EncodeToString(jwtClaims(claims))
+	sig := h.jwtSignature([]byte(in), alg, key)
+	return in + "." + jwtEncoding.
+		EncodeToString(sig)
+}
@@ -0,0 +1,154 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pemKey(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type: "PRIVATE KEY", Bytes: der,
+	}))
+}
+
+func jwtParts(t *testing.T, token string) ([]byte, map[string]interface{}, []byte) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("bad token %s", token)
+	}
+
+	var claims map[string]interface{}
+	b, _ := jwtEncoding.DecodeString(parts[1])
+	sig, _ := jwtEncoding.DecodeString(parts[2])
+	json.Unmarshal(b, &claims)
+	return []byte(parts[0] + "." + parts[1]),
+		claims, sig
+}
+
+func TestJWTSign(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	helpers := New(template.New("envp"))
+	claims := map[string]interface{}{
+		"sub": "svc",
+		"exp": 1,
+	}
+
+	type TestStruct struct {
+		alg         string
+		key         string
+		description string
+		verify      func(in, sig []byte) bool
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			alg:         "HS256",
+			key:         "secret",
+			description: "it works with HS256",
+			verify: func(in, sig []byte) bool {
+				mac := hmac.New(sha256.New, []byte("secret"))
+				mac.Write(in)
+				return hmac.Equal(mac.Sum(nil), sig)
+			},
+		},
+		TestStruct{
+			alg:         "RS256",
+			key:         pemKey(t, rsaKey),
+			description: "it works with RS256",
+			verify: func(in, sig []byte) bool {
+				sum := sha256.Sum256(in)
+				return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, sum[:], sig) == nil
+			},
+		},
+		TestStruct{
+			alg:         "ES256",
+			key:         pemKey(t, ecKey),
+			description: "it works with ES256",
+			verify: func(in, sig []byte) bool {
+				sum := sha256.Sum256(in)
+				r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+				return len(sig) == 64 && ecdsa.Verify(&ecKey.PublicKey, sum[:], r, s)
+			},
+		},
+		TestStruct{
+			alg:         "EdDSA",
+			key:         pemKey(t, edKey),
+			description: "it works with EdDSA",
+			verify: func(in, sig []byte) bool {
+				return ed25519.Verify(edPub, in, sig)
+			},
+		},
+	} {
+		token := helpers.JWTSign(claims, test.alg, test.key)
+		in, actual, sig := jwtParts(t, token)
+		assert.Equal(t, "svc", actual["sub"], test.description)
+		assert.True(t, test.verify(in, sig), test.description)
+	}
+}
+
+func TestJWTSign__curves(t *testing.T) {
+	helpers := New(template.New("envp"))
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	token := helpers.JWTSign(`{"sub":"svc"}`, "ES384", pemKey(t, p384))
+	_, _, sig := jwtParts(t, token)
+	assert.Equal(t, 96, len(sig), "ES384 works with P-384")
+
+	assertFatal(t, func() {
+		helpers.JWTSign(`{"sub":"svc"}`, "ES256", pemKey(t, p384))
+	}, "ES256 doesn't work with P-384")
+}
+
+func TestJWTSign__file(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "envp")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "key.pem")
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ioutil.WriteFile(path, []byte(pemKey(t, edKey)), 0600)
+	helpers := New(template.New("envp"))
+
+	assertFatal(t, func() {
+		helpers.JWTSign(`{"sub":"svc"}`, "EdDSA", path)
+	}, "it can't read keys outside of --allow-read")
+
+	helpers.AllowRead = []string{dir}
+	in, _, sig := jwtParts(t, helpers.JWTSign(`{"sub":"svc"}`, "EdDSA", path))
+	assert.True(t, ed25519.Verify(edPub, in, sig),
+		"it reads keys inside of --allow-read")
+}
+
+func TestJWTSign__json(t *testing.T) {
+	helpers := New(template.New("envp"))
+	token := helpers.JWTSign(`{"sub":"svc"}`, "HS256", "secret")
+	_, claims, _ := jwtParts(t, token)
+	assert.Equal(t, "svc", claims["sub"])
+}
@@ -0,0 +1,203 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	"github.com/envygeeks/envp/state"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// SSHKind, and WireguardKind are the kinds
+	// we give keypairs that we persist for you.
+	SSHKind       = "ssh"
+	WireguardKind = "wireguard"
+
+	sshComment   = "envp"
+	sshMagic     = "openssh-key-v1\x00"
+	sshBlockSize = 8
+)
+
+var (
+	// sshTypes are the public key types of each of
+	// the algorithms, so we know what was persisted.
+	sshTypes = map[string]string{
+		"ed25519": ssh.KeyAlgoED25519,
+		"ecdsa":   ssh.KeyAlgoECDSA256,
+		"rsa":     ssh.KeyAlgoRSA,
+	}
+)
+
+// Keypair is a private, and public key, in
+// whatever format that the consumer expects.
+type Keypair struct {
+	Private string `json:"private"`
+	Public  string `json:"public"`
+}
+
+// sshString appends a length prefixed string, the
+// way that the SSH wire format encodes strings.
+func sshString(b []byte, s []byte) []byte {
+	l := make([]byte, 4)
+	binary.BigEndian.PutUint32(l, uint32(len(s)))
+	return append(append(b, l...), s...)
+}
+
+// sshEd25519 encodes an ed25519 key the way that
+// OpenSSH does, it has no PEM, or PKCS#8 for them.
+func sshEd25519(pub ed25519.PublicKey, priv ed25519.PrivateKey, comment string) []byte {
+	check := randomBytes(4)
+	pubKey := sshString(sshString(nil, []byte(ssh.KeyAlgoED25519)), pub)
+	section := append(append([]byte{}, check...), check...)
+	section = sshString(section, []byte(ssh.KeyAlgoED25519))
+	section = sshString(section, pub)
+	section = sshString(section, priv)
+	section = sshString(section, []byte(comment))
+	for i := 1; len(section)%sshBlockSize != 0; i++ {
+		section = append(section, byte(i))
+	}
+
+	out := []byte(sshMagic)
+	out = sshString(out, []byte("none"))
+	out = sshString(out, []byte("none"))
+	out = sshString(out, nil)
+	out = append(out, 0, 0, 0, 1)
+	out = sshString(out, pubKey)
+	out = sshString(out, section)
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "OPENSSH PRIVATE KEY",
+		Bytes: out,
+	})
+}
+
+// sshKeypair generates an SSH keypair, ed25519 is in
+// OpenSSH's format, rsa, and ecdsa are PEM which every
+// version of OpenSSH, and most other servers, accept.
+func sshKeypair(algo string) *Keypair {
+	var (
+		private []byte
+		public  interface{}
+	)
+
+	switch algo {
+	case "ed25519":
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		private, public = sshEd25519(pub, priv, sshComment), pub
+	case "rsa":
+		key, err := rsa.GenerateKey(rand.Reader, 4096)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		der := x509.MarshalPKCS1PrivateKey(key)
+		private, public = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der}), &key.PublicKey
+	case "ecdsa":
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		private, public = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), &key.PublicKey
+	default:
+		logrus.Fatalf("unknown ssh key algorithm %s", algo)
+	}
+
+	pub, err := ssh.NewPublicKey(public)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+	return &Keypair{
+		Public:  authorized + " " + sshComment,
+		Private: string(private),
+	}
+}
+
+// SSHKeypair generates an SSH keypair (ed25519, rsa,
+// or ecdsa) if you give it a name, it's persisted, so
+// that it doesn't change between each of your renders,
+// unless you ask for another algorithm, then it's new.
+func (h *Helpers) SSHKeypair(algo string, name ...string) *Keypair {
+	if len(name) == 0 {
+		return sshKeypair(algo)
+	}
+
+	valid := func(s *state.Secret) bool {
+		kp := &Keypair{}
+		if s.Kind != SSHKind || json.Unmarshal([]byte(s.Value), kp) != nil {
+			return false
+		}
+
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(kp.Public))
+		return err == nil && pub.Type() == sshTypes[algo]
+	}
+
+	out := &Keypair{}
+	h.persistJSON(SSHKind+"/"+name[0], SSHKind, "", out, valid, func() interface{} {
+		return sshKeypair(algo)
+	})
+
+	return out
+}
+
+// wireguardKeypair generates a Curve25519 keypair
+// in base64, the way that `wg genkey` gives it to you.
+func wireguardKeypair() *Keypair {
+	var priv, pub [32]byte
+	copy(priv[:], randomBytes(32))
+	priv[0] &= 248
+	priv[31] &= 127
+	priv[31] |= 64
+
+	curve25519.ScalarBaseMult(&pub, &priv)
+	return &Keypair{
+		Private: base64.StdEncoding.EncodeToString(priv[:]),
+		Public:  base64.StdEncoding.EncodeToString(pub[:]),
+	}
+}
+
+// WireguardKeypair generates a WireGuard keypair, if
+// you give it a name, it's persisted, so that it doesn't
+// change between each of your renders.
+func (h *Helpers) WireguardKeypair(name ...string) *Keypair {
+	if len(name) == 0 {
+		return wireguardKeypair()
+	}
+
+	valid := func(s *state.Secret) bool {
+		return s.Kind == WireguardKind
+	}
+
+	out := &Keypair{}
+	h.persistJSON(WireguardKind+"/"+name[0], WireguardKind, "", out, valid, func() interface{} {
+		return wireguardKeypair()
+	})
+
+	return out
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHKeypair(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, algo := range []string{"ed25519", "rsa", "ecdsa"} {
+		pair := helpers.SSHKeypair(algo)
+		signer, err := ssh.ParsePrivateKey([]byte(pair.Private))
+		if assert.Nil(t, err, algo) {
+			public := string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+			assert.True(t, strings.HasPrefix(pair.Public, strings.TrimSpace(public)),
+				algo)
+		}
+	}
+}
+
+func TestSSHKeypair__persisted(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "test-ssh-keypair")
+	defer os.RemoveAll(dir)
+
+	helpers := New(template.New("envp"))
+	helpers.StateFile = filepath.Join(dir, "state.json")
+	first := helpers.SSHKeypair("ed25519", "host")
+	assert.Equal(t, first, helpers.SSHKeypair("ed25519", "host"))
+	assert.NotEqual(t, first, helpers.SSHKeypair("ed25519"))
+
+	ecdsa := helpers.SSHKeypair("ecdsa", "host")
+	assert.NotEqual(t, first, ecdsa, "it regenerates for a new algorithm")
+	assert.True(t, strings.HasPrefix(ecdsa.Public, "ecdsa-sha2-nistp256 "))
+	assert.Equal(t, ecdsa, helpers.SSHKeypair("ecdsa", "host"))
+}
+
+func TestWireguardKeypair(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "test-wireguard-keypair")
+	defer os.RemoveAll(dir)
+
+	helpers := New(template.New("envp"))
+	helpers.StateFile = filepath.Join(dir, "state.json")
+	pair := helpers.WireguardKeypair("wg0")
+	priv, _ := base64.StdEncoding.DecodeString(pair.Private)
+	if assert.Len(t, priv, 32) {
+		var in, out [32]byte
+		copy(in[:], priv)
+		curve25519.ScalarBaseMult(&out, &in)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(out[:]),
+			pair.Public)
+	}
+
+	assert.Equal(t, pair, helpers.WireguardKeypair("wg0"))
+}
@@ -0,0 +1,240 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	consulScheme   = "consul"
+	consulEnv      = "CONSUL_HTTP_ADDR"
+	consulTokenEnv = "CONSUL_HTTP_TOKEN"
+	consulFallback = "127.0.0.1:8500"
+	etcdScheme     = "etcd"
+	etcdEnv        = "ETCD_ADDR"
+	etcdFallback   = "127.0.0.1:2379"
+)
+
+var (
+	kvClient = &http.Client{
+		Timeout: 10 * time.Second,
+	}
+)
+
+// kvAddr resolves the address for a provider, it
+// prefers what you set on Helpers, then the env,
+// and finally it falls back to the local agent.
+func kvAddr(addr, env, fallback string) string {
+	if addr == "" {
+		addr = os.Getenv(env)
+		if addr == "" {
+			addr = fallback
+		}
+	}
+
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	return strings.TrimSuffix(addr, "/")
+}
+
+// kvURL splits consul://path/to/key into
+// the scheme, and the key that was requested.
+func kvURL(s string) (string, string) {
+	u, err := url.Parse(s)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	switch u.Scheme {
+	case consulScheme, etcdScheme:
+		return u.Scheme, u.Host + u.Path
+	default:
+		logrus.Fatalf("unsupported kv provider %q", u.Scheme)
+		return "", ""
+	}
+}
+
+// kvDo runs the request, and hands back the body
+// a 404 is returned as nil so callers can decide
+func kvDo(req *http.Request) []byte {
+	logrus.Debugf("requesting %s", req.URL)
+	resp, err := kvClient.Do(req)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		logrus.Fatalf("%s returned %s", req.URL, resp.Status)
+	}
+
+	return body
+}
+
+/**
+ */
+type consulPair struct {
+	Key   string
+	Value []byte
+}
+
+// consul pulls the pairs for a key, or a prefix
+// if you ask it to recurse through the tree
+func (h *Helpers) consul(key string, recurse bool) []consulPair {
+	key = strings.TrimPrefix(key, "/")
+	addr := kvAddr(h.ConsulAddr, consulEnv, consulFallback)
+	u := fmt.Sprintf("%s/v1/kv/%s", addr, key)
+	if recurse {
+		u += "?recurse=true"
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	// An ACL token, the same env the consul CLI uses.
+	if token := os.Getenv(consulTokenEnv); token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+
+	var pairs []consulPair
+	if body := kvDo(req); body != nil {
+		if err := json.Unmarshal(body, &pairs); err != nil {
+			logrus.Fatalln(err)
+		}
+	}
+
+	return pairs
+}
+
+/**
+ */
+type etcdRange struct {
+	Kvs []struct {
+		Key   []byte `json:"key"`
+		Value []byte `json:"value"`
+	} `json:"kvs"`
+}
+
+// etcdRangeEnd returns the key that ends a prefix
+// this is how etcd v3 expects you to ask for a tree
+func etcdRangeEnd(key string) []byte {
+	end := []byte(key)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	return []byte{0}
+}
+
+// etcd pulls the pairs for a key, or a prefix
+// through the v3 json gateway that etcd ships.
+func (h *Helpers) etcd(key string, recurse bool) etcdRange {
+	addr := kvAddr(h.EtcdAddr, etcdEnv, etcdFallback)
+	in := map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key))}
+	if recurse {
+		end := etcdRangeEnd(key)
+		in["range_end"] = base64.StdEncoding.EncodeToString(end)
+	}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	u := addr + "/v3/kv/range"
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	var out etcdRange
+	req.Header.Set("Content-Type", "application/json")
+	if body := kvDo(req); body != nil {
+		if err := json.Unmarshal(body, &out); err != nil {
+			logrus.Fatalln(err)
+		}
+	}
+
+	return out
+}
+
+// Kv pulls a single value out of Consul, or etcd
+// using consul://path/to/key, or etcd:///path/to/key
+func (h *Helpers) Kv(s string) string {
+	scheme, key := kvURL(s)
+	if scheme == consulScheme {
+		if pairs := h.consul(key, false); len(pairs) > 0 {
+			return string(pairs[0].Value)
+		}
+	} else {
+		if out := h.etcd(key, false); len(out.Kvs) > 0 {
+			return string(out.Kvs[0].Value)
+		}
+	}
+
+	// Bad key given.
+	logrus.Fatalf("Unable to find %s", s)
+	return ""
+}
+
+// KvTree lists everything under a prefix as a map,
+// keys are relative to the prefix you asked for, the
+// prefix is a directory, so app doesn't give you apple/
+func (h *Helpers) KvTree(s string) map[string]string {
+	scheme, key := kvURL(s)
+	if key != "" && !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+
+	out := map[string]string{}
+	if scheme == consulScheme {
+		key = strings.TrimPrefix(key, "/")
+		for _, v := range h.consul(key, true) {
+			k := strings.Trim(strings.TrimPrefix(v.Key, key), "/")
+			if k != "" && !strings.HasSuffix(v.Key, "/") {
+				out[k] = string(v.Value)
+			}
+		}
+
+		return out
+	}
+
+	for _, v := range h.etcd(key, true).Kvs {
+		k := strings.Trim(strings.TrimPrefix(string(v.Key), key), "/")
+		if k != "" {
+			out[k] = string(v.Value)
+		}
+	}
+
+	return out
+}
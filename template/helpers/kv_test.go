@@ -0,0 +1,171 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	kvData = map[string]string{
+		"app/db/host":  "db.local",
+		"app/db/port":  "5432",
+		"app/dbx/host": "dbx.local",
+		"app/name":     "envp",
+	}
+)
+
+/**
+ */
+func consulServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		type pair struct {
+			Key   string
+			Value []byte
+		}
+
+		var pairs []pair
+		key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+		for k, v := range kvData {
+			if k == key || (r.URL.Query().Get("recurse") != "" && strings.HasPrefix(k, key)) {
+				pairs = append(pairs, pair{Key: k, Value: []byte(v)})
+			}
+		}
+
+		if len(pairs) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(pairs)
+	}))
+}
+
+/**
+ */
+func etcdServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		type kv struct {
+			Key   []byte `json:"key"`
+			Value []byte `json:"value"`
+		}
+
+		var in struct {
+			Key      []byte `json:"key"`
+			RangeEnd []byte `json:"range_end"`
+		}
+
+		json.NewDecoder(r.Body).Decode(&in)
+		out := map[string][]kv{"kvs": {}}
+		for k, v := range kvData {
+			k = "/" + k
+			if k == string(in.Key) || (in.RangeEnd != nil && k >= string(in.Key) && k < string(in.RangeEnd)) {
+				out["kvs"] = append(out["kvs"], kv{Key: []byte(k), Value: []byte(v)})
+			}
+		}
+
+		json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func TestKv(t *testing.T) {
+	consul, etcd := consulServer(), etcdServer()
+	defer consul.Close()
+	defer etcd.Close()
+
+	type TestStruct struct {
+		expected    string
+		description string
+		key         string
+	}
+
+	helpers := New(template.New("envp"))
+	helpers.ConsulAddr, helpers.EtcdAddr = consul.URL, etcd.URL
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "db.local",
+			description: "it works with consul",
+			key:         "consul://app/db/host",
+		},
+		TestStruct{
+			expected:    "5432",
+			description: "it works with etcd",
+			key:         "etcd:///app/db/port",
+		},
+	} {
+		actual := helpers.Kv(test.key)
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestKvTree(t *testing.T) {
+	consul, etcd := consulServer(), etcdServer()
+	defer consul.Close()
+	defer etcd.Close()
+
+	type TestStruct struct {
+		expected    map[string]string
+		description string
+		key         string
+	}
+
+	helpers := New(template.New("envp"))
+	helpers.ConsulAddr, helpers.EtcdAddr = consul.URL, etcd.URL
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    map[string]string{"host": "db.local", "port": "5432"},
+			description: "it lists a prefix with consul",
+			key:         "consul://app/db",
+		},
+		TestStruct{
+			expected:    map[string]string{"host": "db.local", "port": "5432"},
+			description: "it lists a prefix with etcd",
+			key:         "etcd:///app/db",
+		},
+		TestStruct{
+			expected:    map[string]string{},
+			description: "it's empty if nothing exists",
+			key:         "consul://unknown",
+		},
+	} {
+		actual := helpers.KvTree(test.key)
+		assert.Equal(t, test.expected, actual,
+			test.description)
+	}
+}
+
+func TestKv__consulToken(t *testing.T) {
+	token := ""
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("X-Consul-Token")
+		json.NewEncoder(w).Encode([]consulPair{{Key: "a", Value: []byte("b")}})
+	}))
+
+	defer consul.Close()
+	defer os.Unsetenv(consulTokenEnv)
+	os.Setenv(consulTokenEnv, "secret")
+	helpers := New(template.New("envp"))
+	helpers.ConsulAddr = consul.URL
+	helpers.Kv("consul://a")
+
+	assert.Equal(t, "secret", token)
+}
+
+func TestEtcdRangeEnd(t *testing.T) {
+	actual := etcdRangeEnd("/app")
+	expected := base64.StdEncoding.EncodeToString([]byte("/apq"))
+	assert.Equal(t, expected, base64.StdEncoding.
+		EncodeToString(actual))
+}
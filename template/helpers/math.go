@@ -0,0 +1,239 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+/**
+ * num is a number that's an int, or a float, the
+ * math helpers give you back an int if all of the
+ * numbers you gave them were ints, or a float if not.
+ */
+type num struct {
+	i     int64
+	f     float64
+	isInt bool
+}
+
+// toNum takes any int, float, or a string (like what
+// env gives you) with a number in it, and makes a num.
+func toNum(v interface{}) num {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return num{i: val.Int(), f: float64(val.Int()), isInt: true}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return num{i: int64(val.Uint()), f: float64(val.Uint()), isInt: true}
+	case reflect.Float32, reflect.Float64:
+		return num{f: val.Float()}
+	case reflect.String:
+		s := strings.TrimSpace(val.String())
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return num{i: i, f: float64(i), isInt: true}
+		}
+
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return num{f: f}
+		}
+	}
+
+	logrus.Fatalf("expected a number, not %#v", v)
+	return num{}
+}
+
+// value gives you back the int, or the float, it's
+// an int (not int64) so it works with repeat, and friends,
+// unless it doesn't fit, like a memoryLimit on 386, or arm.
+func (n num) value() interface{} {
+	if n.isInt {
+		if int64(int(n.i)) != n.i {
+			return n.i
+		}
+
+		return int(n.i)
+	}
+
+	return n.f
+}
+
+// reduce runs the int, or float op over the numbers
+func reduce(vs []interface{}, i func(a, b int64) int64, f func(a, b float64) float64) interface{} {
+	if len(vs) == 0 {
+		logrus.Fatalln("expected at least one number")
+	}
+
+	out := toNum(vs[0])
+	for _, v := range vs[1:] {
+		n := toNum(v)
+		if out.isInt && n.isInt {
+			out.i = i(out.i, n.i)
+			out.f = float64(out.i)
+			continue
+		}
+
+		out = num{f: f(out.f, n.f)}
+	}
+
+	return out.value()
+}
+
+// Add adds all of the numbers
+func (h *Helpers) Add(vs ...interface{}) interface{} {
+	return reduce(vs,
+		func(a, b int64) int64 { return a + b },
+		func(a, b float64) float64 { return a + b })
+}
+
+// Sub subtracts b from a
+func (h *Helpers) Sub(a, b interface{}) interface{} {
+	return reduce([]interface{}{a, b},
+		func(a, b int64) int64 { return a - b },
+		func(a, b float64) float64 { return a - b })
+}
+
+// Mul multiplies all of the numbers
+func (h *Helpers) Mul(vs ...interface{}) interface{} {
+	return reduce(vs,
+		func(a, b int64) int64 { return a * b },
+		func(a, b float64) float64 { return a * b })
+}
+
+// Div divides a by b, ints are divided as ints
+func (h *Helpers) Div(a, b interface{}) interface{} {
+	if toNum(b).f == 0 {
+		logrus.Fatalln("unable to divide by zero")
+	}
+
+	return reduce([]interface{}{a, b},
+		func(a, b int64) int64 { return a / b },
+		func(a, b float64) float64 { return a / b })
+}
+
+// Mod gives you the remainder of a divided by b
+func (h *Helpers) Mod(a, b interface{}) interface{} {
+	if toNum(b).f == 0 {
+		logrus.Fatalln("unable to divide by zero")
+	}
+
+	return reduce([]interface{}{a, b},
+		func(a, b int64) int64 { return a % b },
+		math.Mod)
+}
+
+// Max gives you the biggest of the numbers
+func (h *Helpers) Max(vs ...interface{}) interface{} {
+	return reduce(vs,
+		func(a, b int64) int64 {
+			if b > a {
+				return b
+			}
+
+			return a
+		}, math.Max)
+}
+
+// Min gives you the smallest of the numbers
+func (h *Helpers) Min(vs ...interface{}) interface{} {
+	return reduce(vs,
+		func(a, b int64) int64 {
+			if b < a {
+				return b
+			}
+
+			return a
+		}, math.Min)
+}
+
+// Ceil rounds a number up to an int
+func (h *Helpers) Ceil(v interface{}) int {
+	return int(math.Ceil(toNum(v).f))
+}
+
+// Floor rounds a number down to an int
+func (h *Helpers) Floor(v interface{}) int {
+	return int(math.Floor(toNum(v).f))
+}
+
+// Round rounds a number half away from zero, to an
+// int, or to a float with the number of places you ask.
+func (h *Helpers) Round(v interface{}, places ...int) interface{} {
+	f := toNum(v).f
+	if len(places) == 0 || places[0] <= 0 {
+		return int(math.Round(f))
+	}
+
+	pow := math.Pow(10, float64(places[0]))
+	return math.Round(f*pow) / pow
+}
+
+// Percent gives you p percent of n, if they are both
+// ints you get an int back (rounded down) so that you
+// can use it as a worker count, or a memory limit.
+func (h *Helpers) Percent(p, n interface{}) interface{} {
+	pn, nn := toNum(p), toNum(n)
+	if pn.isInt && nn.isInt {
+		return num{i: nn.i * pn.i / 100, isInt: true}.value()
+	}
+
+	return nn.f * pn.f / 100
+}
+
+var (
+	bytesRegex = regexp.MustCompile(`(?i)^\s*([0-9]*\.?[0-9]+)\s*([kmgtpe]?)(i?)b?\s*$`)
+	bytesUnits = "kmgtpe"
+)
+
+// ParseBytes parses a size like 512Mi (1024), or
+// 512M (1000) into bytes, a plain number is bytes, and
+// an i needs a unit in front of it (512i isn't a size.)
+func (h *Helpers) ParseBytes(s string) int64 {
+	m := bytesRegex.FindStringSubmatch(s)
+	if m == nil || (m[3] != "" && m[2] == "") {
+		logrus.Fatalf("unable to parse %q as bytes", s)
+	}
+
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	base := 1000.0
+	if m[3] != "" {
+		base = 1024
+	}
+
+	exp := strings.Index(bytesUnits, strings.ToLower(m[2])) + 1
+	if m[2] == "" {
+		exp = 0
+	}
+
+	return int64(math.Round(f * math.Pow(base, float64(exp))))
+}
+
+// FormatBytes formats bytes with the biggest binary
+// unit (Ki, Mi, Gi...) that it fits into, like 512Mi.
+func (h *Helpers) FormatBytes(v interface{}) string {
+	b, exp := toNum(v).f, 0
+	for exp < len(bytesUnits) && math.Abs(b) >= 1024 {
+		b /= 1024
+		exp++
+	}
+
+	out := strconv.FormatFloat(math.Round(b*100)/100, 'f', -1, 64)
+	if exp == 0 {
+		return out
+	}
+
+	return fmt.Sprintf("%s%si", out, strings.ToUpper(bytesUnits[exp-1:exp]))
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"os"
+	"testing"
+	"text/template"
+)
+
+func TestMath(t *testing.T) {
+	os.Setenv("ENVP_TEST_CPUS", "4")
+	defer os.Unsetenv("ENVP_TEST_CPUS")
+
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "6 3.5 9",
+			description: "add works with ints, floats, and strings",
+			input:       `{{ add 1 2 3 }} {{ add 1 2.5 }} {{ add (env "envp_test_cpus") 5 }}`,
+		},
+		TestStruct{
+			expected:    "7 -0.5",
+			description: "sub subtracts b from a",
+			input:       `{{ sub 10 3 }} {{ sub 1 1.5 }}`,
+		},
+		TestStruct{
+			expected:    "9 5",
+			description: "mul multiplies, and works in pipelines",
+			input:       `{{ env "envp_test_cpus" | mul 2 | add 1 }} {{ mul 2 2.5 }}`,
+		},
+		TestStruct{
+			expected:    "3 3.5 1 1.5",
+			description: "div, and mod keep ints as ints",
+			input:       `{{ div 7 2 }} {{ div 7 2.0 }} {{ mod 7 2 }} {{ mod 7.5 2 }}`,
+		},
+		TestStruct{
+			expected:    "4 1 2.5",
+			description: "max, and min",
+			input:       `{{ max 1 4 "2" }} {{ min 3 1 2 }} {{ max 1 2.5 }}`,
+		},
+		TestStruct{
+			expected:    "3 2 3 2.57",
+			description: "ceil, floor, and round",
+			input:       `{{ ceil 2.1 }} {{ floor "2.9" }} {{ round 2.5 }} {{ round 2.5678 2 }}`,
+		},
+		TestStruct{
+			expected:    "3 402653184 37.5",
+			description: "percent gives you p percent of n",
+			input:       `{{ env "envp_test_cpus" | percent 75 }} {{ parseBytes "512Mi" | percent 75 }} {{ percent 75 50.0 }}`,
+		},
+		TestStruct{
+			expected:    "6442450944 8589934593",
+			description: "it doesn't overflow an int on 32-bit",
+			input:       `{{ parseBytes "8Gi" | percent 75 }} {{ add (parseBytes "8Gi") 1 }}`,
+		},
+		TestStruct{
+			expected:    "536870912 512000000 1536 100",
+			description: "parseBytes parses binary, and decimal units",
+			input:       `{{ parseBytes "512Mi" }} {{ parseBytes "512MB" }} {{ parseBytes "1.5Ki" }} {{ parseBytes "100" }}`,
+		},
+		TestStruct{
+			expected:    "512Mi 1.5Gi 100 1Ki",
+			description: "formatBytes formats binary units",
+			input:       `{{ formatBytes 536870912 }} {{ parseBytes "1.5Gi" | formatBytes }} {{ formatBytes 100 }} {{ formatBytes "1024" }}`,
+		},
+		TestStruct{
+			expected:    "true xxx",
+			description: "you can use the results",
+			input:       `{{ gt (add 1 2) 2 }} {{ "x" | repeat (add 1 2) }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
+
+func TestParseBytes__fatal(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, s := range []string{"512i", "512ib", "nope"} {
+		assertFatal(t, func() {
+			helpers.ParseBytes(s)
+		}, s)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"math/big"
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// parseCIDR parses a prefix, like 10.0.0.0/24
+func parseCIDR(prefix string) *net.IPNet {
+	_, network, err := net.ParseCIDR(strings.TrimSpace(prefix))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return network
+}
+
+// parseIP parses an address, or the address in
+// a CIDR (like 10.0.0.5/24) so you can pass either.
+func parseIP(s string) net.IP {
+	s = strings.TrimSpace(s)
+	if ip, _, err := net.ParseCIDR(s); err == nil {
+		return ip
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		logrus.Fatalf("invalid ip %q", s)
+	}
+
+	return ip
+}
+
+// normalizeIP gives you the 4 byte form of IPv4
+func normalizeIP(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+
+	return ip
+}
+
+// ipToInt, and intToIP let us do math on addresses
+func ipToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(normalizeIP(ip))
+}
+
+func intToIP(n *big.Int, size int) net.IP {
+	b := n.Bytes()
+	out := make(net.IP, size)
+	copy(out[size-len(b):], b)
+	return out
+}
+
+// CidrHost gives you the nth address in a prefix, a
+// negative n counts back from the end, like Terraform.
+func (h *Helpers) CidrHost(prefix string, n int) string {
+	network := parseCIDR(prefix)
+	ones, bits := network.Mask.Size()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+
+	num := big.NewInt(int64(n))
+	if n < 0 {
+		num.Add(num, size)
+	}
+
+	if num.Sign() < 0 || num.Cmp(size) >= 0 {
+		logrus.Fatalf("%s doesn't have a host %d", prefix, n)
+	}
+
+	ip := ipToInt(network.IP)
+	return intToIP(ip.Add(ip, num), len(normalizeIP(network.IP))).String()
+}
+
+// CidrSubnet gives you the nth subnet of a prefix,
+// that's newbits longer, like Terraform's cidrsubnet.
+func (h *Helpers) CidrSubnet(prefix string, newbits, n int) string {
+	network := parseCIDR(prefix)
+	ones, bits := network.Mask.Size()
+	if newbits < 0 || ones+newbits > bits {
+		logrus.Fatalf("unable to add %d bits to %s", newbits, prefix)
+	}
+
+	max := new(big.Int).Lsh(big.NewInt(1), uint(newbits))
+	if n < 0 || big.NewInt(int64(n)).Cmp(max) >= 0 {
+		logrus.Fatalf("%s doesn't have a subnet %d with %d more bits", prefix, n, newbits)
+	}
+
+	num := new(big.Int).Lsh(big.NewInt(int64(n)), uint(bits-ones-newbits))
+	ip := ipToInt(network.IP)
+	subnet := &net.IPNet{
+		IP:   intToIP(ip.Add(ip, num), len(normalizeIP(network.IP))),
+		Mask: net.CIDRMask(ones+newbits, bits),
+	}
+
+	return subnet.String()
+}
+
+// CidrContains tells you if a prefix has the address,
+// or all of a smaller prefix (like 10.0.1.0/24) in it.
+func (h *Helpers) CidrContains(prefix, s string) bool {
+	network := parseCIDR(prefix)
+	if _, sub, err := net.ParseCIDR(strings.TrimSpace(s)); err == nil {
+		ones, _ := network.Mask.Size()
+		subOnes, _ := sub.Mask.Size()
+		return subOnes >= ones && network.Contains(sub.IP)
+	}
+
+	return network.Contains(parseIP(s))
+}
+
+// CidrNetmask gives you the netmask of an IPv4 prefix
+func (h *Helpers) CidrNetmask(prefix string) string {
+	network := parseCIDR(prefix)
+	if len(network.Mask) != net.IPv4len {
+		logrus.Fatalf("%s isn't IPv4, so it has no netmask", prefix)
+	}
+
+	return net.IP(network.Mask).String()
+}
+
+// IPVersion gives you 4, or 6
+func (h *Helpers) IPVersion(s string) int {
+	if parseIP(s).To4() != nil {
+		return 4
+	}
+
+	return 6
+}
+
+// IPIsPrivate tells you if the address is private,
+// it's RFC 1918 for IPv4, and RFC 4193 for IPv6.
+func (h *Helpers) IPIsPrivate(s string) bool {
+	return parseIP(s).IsPrivate()
+}
+
+// addrIPs gives you the IPs out of interface addrs
+func addrIPs(addrs []net.Addr) []string {
+	out := []string{}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok {
+			out = append(out, ipnet.IP.String())
+		}
+	}
+
+	return out
+}
+
+// InterfaceAddrs gives you the IPs of an interface
+func (h *Helpers) InterfaceAddrs(name string) []string {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return addrIPs(addrs)
+}
+
+// PrimaryIP gives you the IP that we'd use to get out
+// (nothing is sent) or the first IPv4 that isn't loopback
+func (h *Helpers) PrimaryIP() string {
+	if conn, err := net.Dial("udp", "203.0.113.1:9"); err == nil {
+		defer conn.Close()
+		if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+			return addr.IP.String()
+		}
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok {
+			if !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+				return ipnet.IP.String()
+			}
+		}
+	}
+
+	logrus.Fatalln("unable to find the primary ip")
+	return ""
+}
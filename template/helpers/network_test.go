@@ -0,0 +1,91 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetwork(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "10.0.0.5 10.0.0.254 fd00::10",
+			description: "cidrHost gives you the nth host",
+			input:       `{{ cidrHost "10.0.0.0/24" 5 }} {{ cidrHost "10.0.0.0/24" -2 }} {{ cidrHost "fd00::/64" 16 }}`,
+		},
+		TestStruct{
+			expected:    "10.0.1.0/24 10.0.0.16/28 fd00:0:0:2::/64",
+			description: "cidrSubnet gives you the nth subnet",
+			input:       `{{ cidrSubnet "10.0.0.0/16" 8 1 }} {{ cidrSubnet "10.0.0.5/24" 4 1 }} {{ cidrSubnet "fd00::/48" 16 2 }}`,
+		},
+		TestStruct{
+			expected:    "true false true false",
+			description: "cidrContains checks addresses, and prefixes",
+			input:       `{{ cidrContains "10.0.0.0/16" "10.0.3.4" }} {{ cidrContains "10.0.0.0/16" "10.1.0.1" }} {{ cidrContains "10.0.0.0/16" "10.0.1.0/24" }} {{ cidrContains "10.0.0.0/16" "10.0.0.0/8" }}`,
+		},
+		TestStruct{
+			expected:    "255.255.255.0 255.255.240.0",
+			description: "cidrNetmask gives you the netmask",
+			input:       `{{ cidrNetmask "10.0.0.0/24" }} {{ cidrNetmask "172.16.0.0/20" }}`,
+		},
+		TestStruct{
+			expected:    "4 6 4",
+			description: "ipVersion gives you the version",
+			input:       `{{ ipVersion "10.0.0.1" }} {{ ipVersion "fd00::1" }} {{ ipVersion "10.0.0.1/24" }}`,
+		},
+		TestStruct{
+			expected:    "true true false false",
+			description: "ipIsPrivate checks RFC 1918, and 4193",
+			input:       `{{ ipIsPrivate "10.1.2.3" }} {{ ipIsPrivate "fd00::1" }} {{ ipIsPrivate "8.8.8.8" }} {{ ipIsPrivate "172.32.0.1" }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
+
+// loopback finds the name of the loopback interface
+func loopback() string {
+	ifaces, _ := net.Interfaces()
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			return iface.Name
+		}
+	}
+
+	return ""
+}
+
+func TestInterfaceAddrs(t *testing.T) {
+	lo := loopback()
+	if lo == "" {
+		t.Skip("there's no loopback interface")
+	}
+
+	tpl := template.New("envp")
+	New(tpl)
+
+	var out strings.Builder
+	template.Must(tpl.Parse(`{{ range interfaceAddrs . }}{{ if eq (ipVersion .) 4 }}{{ . }}{{ end }}{{ end }}`))
+	if assert.Nil(t, tpl.Execute(&out, lo)) {
+		assert.Equal(t, "127.0.0.1", out.String(),
+			"it gives you the ips")
+	}
+}
+
+func TestPrimaryIP(t *testing.T) {
+	ip := net.ParseIP(New(template.New("envp")).PrimaryIP())
+	assert.NotNil(t, ip)
+}
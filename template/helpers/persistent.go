@@ -0,0 +1,97 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/envygeeks/envp/state"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// PasswordKind is the kind we give to
+	// passwords so `secrets rotate` knows them.
+	PasswordKind = "password"
+
+	passwordPrefix = "password/"
+)
+
+// fingerprint hashes the params that something was
+// generated with, so we know when they've changed.
+func fingerprint(params ...interface{}) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%q", params)))
+	return hex.EncodeToString(sum[:])
+}
+
+// persist looks up name in the state file, and if it
+// doesn't exist (or isn't valid) it generates, and stores
+// it, the state is locked the entire time so it's safe,
+// when params isn't "" it has to match what's stored.
+func (h *Helpers) persist(name, params string, valid func(*state.Secret) bool, gen func() *state.Secret) *state.Secret {
+	s, err := state.Open(h.StateFile)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	defer s.Close()
+	if secret := s.Get(name); secret != nil && (params == "" || secret.Params == params) &&
+		(valid == nil || valid(secret)) {
+		logrus.Debugf("using persisted %s", name)
+		return secret
+	}
+
+	secret := gen()
+	secret.Params = params
+	logrus.Debugf("persisting %s", name)
+	s.Set(name, secret)
+	if err := s.Save(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return secret
+}
+
+// PersistentPassword generates a password once, and
+// then returns the same password on every other render,
+// until you ask for another length, then it's regenerated.
+func (h *Helpers) PersistentPassword(name string, size uint) string {
+	valid := func(s *state.Secret) bool {
+		return s.Kind == PasswordKind && s.Size == size
+	}
+
+	secret := h.persist(passwordPrefix+name, "", valid, func() *state.Secret {
+		return &state.Secret{
+			Value: Password(size),
+			Kind:  PasswordKind,
+			Size:  size,
+		}
+	})
+
+	return secret.Value
+}
+
+// persistJSON is persist for values that are structs,
+// they're stored as JSON, and decoded back into out.
+func (h *Helpers) persistJSON(name, kind, params string, out interface{}, valid func(*state.Secret) bool, gen func() interface{}) {
+	secret := h.persist(name, params, valid, func() *state.Secret {
+		b, err := json.Marshal(gen())
+		if err != nil {
+			logrus.Fatalln(err)
+		}
+
+		return &state.Secret{
+			Value: string(b),
+			Kind:  kind,
+		}
+	})
+
+	if err := json.Unmarshal([]byte(secret.Value), out); err != nil {
+		logrus.Fatalln(err)
+	}
+}
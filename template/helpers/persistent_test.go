@@ -0,0 +1,49 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentPassword(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "test-persistent-password")
+	defer os.RemoveAll(dir)
+
+	helpers := New(template.New("envp"))
+	helpers.StateFile = filepath.Join(dir, "state.json")
+	first := helpers.PersistentPassword("db", 32)
+	assert.Equal(t, 32, len(first))
+	assert.Equal(t, first, helpers.PersistentPassword("db", 32))
+	assert.NotEqual(t, first, helpers.PersistentPassword("other", 32))
+
+	helpers = New(template.New("envp"))
+	helpers.StateFile = filepath.Join(dir, "state.json")
+	assert.Equal(t, first, helpers.PersistentPassword("db", 32),
+		"it survives between runs")
+
+	changed := helpers.PersistentPassword("db", 16)
+	assert.Equal(t, 16, len(changed), "it's regenerated with the new length")
+	assert.Equal(t, changed, helpers.PersistentPassword("db", 16))
+}
+
+func TestPersistentPassword__kinds(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "test-persistent-password")
+	defer os.RemoveAll(dir)
+
+	helpers := New(template.New("envp"))
+	helpers.StateFile = filepath.Join(dir, "state.json")
+	keypair := helpers.SSHKeypair("ed25519", "db")
+	password := helpers.PersistentPassword("ssh/db", 32)
+	assert.Equal(t, password, helpers.PersistentPassword("ssh/db", 32),
+		"it doesn't collide with other kinds")
+	assert.Equal(t, keypair, helpers.SSHKeypair("ed25519", "db"))
+}
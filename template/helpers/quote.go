@@ -0,0 +1,131 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// quoteEscaped double quotes a string, escaping the
+// backslash, the quote, and anything that isn't
+// printable, the way YAML, TOML, and INI all want.
+func quoteEscaped(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsPrint(r) {
+				b.WriteRune(r)
+				continue
+			}
+
+			if r > 0xffff {
+				fmt.Fprintf(&b, `\U%08X`, r)
+				continue
+			}
+
+			fmt.Fprintf(&b, `\u%04X`, r)
+		}
+	}
+
+	b.WriteByte('"')
+	return b.String()
+}
+
+// QuoteShell single quotes a string for POSIX shells,
+// nothing is special inside of single quotes but the quote
+func (h *Helpers) QuoteShell(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// QuoteJSON quotes a string as a JSON string
+func (h *Helpers) QuoteJSON(s string) string {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// QuoteYAML quotes a string as a double quoted YAML
+// scalar, so it's never read as a bool, number, or null.
+func (h *Helpers) QuoteYAML(s string) string {
+	return quoteEscaped(s)
+}
+
+// QuoteTOML quotes a string as a TOML basic string
+func (h *Helpers) QuoteTOML(s string) string {
+	return quoteEscaped(s)
+}
+
+// QuoteINI quotes a string as a double quoted INI
+// value with backslash escapes, which is what most
+// readers (PHP, git, systemd, and friends) expect.
+func (h *Helpers) QuoteINI(s string) string {
+	return quoteEscaped(s)
+}
+
+// QuoteXML escapes a string so that it's safe to use
+// as XML text, or inside of a quoted XML attribute.
+func (h *Helpers) QuoteXML(s string) string {
+	buf := &bytes.Buffer{}
+	if err := xml.EscapeText(buf, []byte(s)); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return buf.String()
+}
+
+var (
+	nginxReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`,
+		"$", "${dollar}")
+)
+
+// QuoteNginx double quotes a string for nginx, nginx
+// has no way to escape a $, so it's written as ${dollar}
+// and you need `geo $dollar { default "$"; }` in your http.
+func (h *Helpers) QuoteNginx(s string) string {
+	return `"` + nginxReplacer.Replace(s) + `"`
+}
+
+// QuoteSQL single quotes a string as a standard SQL
+// literal, quotes are doubled, and backslashes are left
+// alone (PostgreSQL's standard_conforming_strings.)
+func (h *Helpers) QuoteSQL(s string) string {
+	if strings.ContainsRune(s, 0) {
+		logrus.Fatalln("SQL strings can't contain NUL")
+	}
+
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// QuoteRegex escapes a string so that it matches
+// itself literally inside of a regular expression.
+func (h *Helpers) QuoteRegex(s string) string {
+	return regexp.QuoteMeta(s)
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os/exec"
+	"regexp"
+	"testing"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	adversarial = []string{
+		``,
+		`plain`,
+		`it's "quoted"`,
+		`$HOME ${HOME} $(id) ` + "`id`",
+		`back\slash\\ \n`,
+		"new\nline\r\ttab",
+		"ctrl\x01\x1f\x7f",
+		"yes",
+		"null",
+		"1e3",
+		"- item",
+		"key: value # comment",
+		`<a href="x">&amp;</a>`,
+		"uni \u2028 \ufeff snowman \u2603 \U0001F600",
+		`.*+?()[]{}|^$`,
+	}
+)
+
+func TestQuoteShell(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, v := range adversarial {
+		out, err := exec.Command("sh", "-c", "printf %s "+helpers.QuoteShell(v)).Output()
+		if assert.Nil(t, err, v) {
+			assert.Equal(t, v, string(out))
+		}
+	}
+}
+
+func TestQuoteJSON(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, v := range adversarial {
+		var actual string
+		err := json.Unmarshal([]byte(helpers.QuoteJSON(v)), &actual)
+		if assert.Nil(t, err, v) {
+			assert.Equal(t, v, actual)
+		}
+	}
+}
+
+func TestQuoteYAML(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, v := range adversarial {
+		var actual map[string]interface{}
+		err := yaml.Unmarshal([]byte("key: "+helpers.QuoteYAML(v)), &actual)
+		if assert.Nil(t, err, v) {
+			assert.Equal(t, v, actual["key"])
+		}
+	}
+}
+
+func TestQuoteTOML(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, v := range adversarial {
+		var actual map[string]interface{}
+		_, err := toml.Decode("key = "+helpers.QuoteTOML(v), &actual)
+		if assert.Nil(t, err, v) {
+			assert.Equal(t, v, actual["key"])
+		}
+	}
+}
+
+func TestQuoteXML(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, v := range []string{`it's "quoted"`, `<a href="x">&amp;</a>`, "new\nline\ttab"} {
+		var actual struct {
+			Attr string `xml:"attr,attr"`
+			Text string `xml:",chardata"`
+		}
+
+		q := helpers.QuoteXML(v)
+		err := xml.Unmarshal([]byte(`<a attr="`+q+`">`+q+`</a>`), &actual)
+		if assert.Nil(t, err, v) {
+			assert.Equal(t, v, actual.Attr)
+			assert.Equal(t, v, actual.Text)
+		}
+	}
+}
+
+func TestQuoteINI(t *testing.T) {
+	helpers := New(template.New("envp"))
+	assert.Equal(t, `"a \"b\" \\ \n"`, helpers.QuoteINI("a \"b\" \\ \n"))
+}
+
+func TestQuoteNginx(t *testing.T) {
+	helpers := New(template.New("envp"))
+	assert.Equal(t, `"p\"a${dollar}s\\s"`, helpers.QuoteNginx(`p"a$s\s`))
+}
+
+func TestQuoteSQL(t *testing.T) {
+	helpers := New(template.New("envp"))
+	assert.Equal(t, `'it''s; DROP TABLE x; --'`,
+		helpers.QuoteSQL(`it's; DROP TABLE x; --`))
+}
+
+func TestQuoteRegex(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for _, v := range adversarial {
+		re := regexp.MustCompile("^" + helpers.QuoteRegex(v) + "$")
+		assert.True(t, re.MatchString(v), v)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
+	ambiguous   = "0O1lI|"
+)
+
+var (
+	letters = []rune(digitChars + lowerChars + upperChars)
+)
+
+func rngCheck() {
+	buf := make([]byte, 1)
+	_, err := io.ReadFull(rand.Reader, buf)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+}
+
+// randomIndex picks a number in [0, n) without
+// any modulo bias, rand.Int rejects for us.
+func randomIndex(n int64) int64 {
+	i, err := rand.Int(rand.Reader, big.NewInt(n))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return i.Int64()
+}
+
+// randomBytes reads n cryptographically random bytes
+func randomBytes(n uint) []byte {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return b
+}
+
+// randomRunes picks size runes out of a charset
+func randomRunes(size uint, charset []rune) []rune {
+	if len(charset) == 0 && size > 0 {
+		logrus.Fatalln("charset is empty")
+	}
+
+	out := make([]rune, size)
+	for i := range out {
+		idx := randomIndex(int64(len(charset)))
+		out[i] = charset[idx]
+	}
+
+	return out
+}
+
+// Password generates a password with
+// cryptographically derived random numbers
+func Password(size uint) string {
+	rngCheck()
+
+	out := randomRunes(size, letters)
+	return string(out)
+}
+
+// RandomPassword generates a password with
+// cryptographically derived random numbers
+func (h *Helpers) RandomPassword(size uint) string {
+	return Password(size)
+}
+
+// RandomString generates a string of size using
+// only the characters in the charset you give it.
+func (h *Helpers) RandomString(size uint, charset string) string {
+	rngCheck()
+
+	out := randomRunes(size, []rune(charset))
+	return string(out)
+}
+
+/**
+ */
+type charClass struct {
+	chars string
+	min   uint
+}
+
+// withoutAmbiguous drops characters that are easy
+// to mistake for each other (0/O, 1/l/I, and |)
+func withoutAmbiguous(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(ambiguous, r) {
+			return -1
+		}
+
+		return r
+	}, s)
+}
+
+// passwordClasses parses the options that you
+// pass to randomPasswordWith, into their classes.
+func passwordClasses(opts []string) []*charClass {
+	lower, upper := &charClass{chars: lowerChars}, &charClass{chars: upperChars}
+	digit, symbol := &charClass{chars: digitChars}, &charClass{}
+	classes := map[string]*charClass{
+		"min-lower":   lower,
+		"min-upper":   upper,
+		"min-digits":  digit,
+		"min-symbols": symbol,
+	}
+
+	noAmbiguous := false
+	for _, opt := range opts {
+		switch {
+		case opt == "symbols":
+			symbol.chars = symbolChars
+		case opt == "no-ambiguous":
+			noAmbiguous = true
+		case strings.Contains(opt, "="):
+			kv := strings.SplitN(opt, "=", 2)
+			class, ok := classes[kv[0]]
+			if !ok {
+				logrus.Fatalf("unknown option %s", opt)
+			}
+
+			n, err := strconv.ParseUint(kv[1], 10, 32)
+			if err != nil {
+				logrus.Fatalln(err)
+			}
+
+			class.min = uint(n)
+			if class == symbol {
+				symbol.chars = symbolChars
+			}
+		default:
+			logrus.Fatalf("unknown option %s", opt)
+		}
+	}
+
+	out := []*charClass{lower, upper, digit, symbol}
+	if noAmbiguous {
+		for _, v := range out {
+			v.chars = withoutAmbiguous(v.chars)
+		}
+	}
+
+	return out
+}
+
+// RandomPasswordWith generates a password, with options,
+// "symbols" adds symbols, "no-ambiguous" drops characters
+// that are easily confused, and "min-lower=N", "min-upper=N",
+// "min-digits=N", "min-symbols=N" guarantee a minimum.
+func (h *Helpers) RandomPasswordWith(size uint, opts ...string) string {
+	rngCheck()
+
+	var (
+		out     []rune
+		charset []rune
+		mins    uint
+	)
+
+	for _, class := range passwordClasses(opts) {
+		mins += class.min
+		charset = append(charset, []rune(class.chars)...)
+		out = append(out, randomRunes(class.min, []rune(class.chars))...)
+	}
+
+	if mins > size {
+		logrus.Fatalf("minimums (%d) are more than the size (%d)", mins, size)
+	}
+
+	out = append(out, randomRunes(size-mins, charset)...)
+	for i := len(out) - 1; i > 0; i-- {
+		j := randomIndex(int64(i + 1))
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+// RandomHex generates n random bytes as hex
+func (h *Helpers) RandomHex(n uint) string {
+	return hex.EncodeToString(randomBytes(n))
+}
+
+// RandomBase64 generates n random bytes as base64
+func (h *Helpers) RandomBase64(n uint) string {
+	return base64.StdEncoding.EncodeToString(randomBytes(n))
+}
+
+// RandomInt generates a number in [min, max), the
+// math is done with big ints, so that a span that's
+// wider than an int64 (like the full range) works.
+func (h *Helpers) RandomInt(min, max int64) int64 {
+	if max <= min {
+		logrus.Fatalf("max (%d) must be more than min (%d)", max, min)
+	}
+
+	span := new(big.Int).Sub(big.NewInt(max), big.NewInt(min))
+	i, err := rand.Int(rand.Reader, span)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return i.Add(i, big.NewInt(min)).Int64()
+}
+
+// uuid formats, and versions 16 bytes of a uuid
+func uuid(b []byte, version byte) string {
+	b[6] = (b[6] & 0x0f) | version<<4
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x",
+		b[0:4], b[4:6], b[6:8], b[8:10],
+		b[10:16])
+}
+
+// UUID generates a random (version 4) uuid
+func (h *Helpers) UUID() string {
+	return uuid(randomBytes(16), 4)
+}
+
+// UUIDv7 generates a time ordered (version 7) uuid
+// the first 48 bits are the unix time in milliseconds
+// of the render, so it respects --now too.
+func (h *Helpers) UUIDv7() string {
+	b, ts := randomBytes(16), make([]byte, 8)
+	binary.BigEndian.PutUint64(ts, uint64(h.Now().UnixNano()/int64(time.Millisecond)))
+	copy(b[0:6], ts[2:8])
+	return uuid(b, 7)
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLetters(t *testing.T) {
+	seen := map[rune]bool{}
+	for _, v := range letters {
+		assert.False(t, seen[v], "it has no duplicates")
+		seen[v] = true
+	}
+}
+
+func TestRandomString(t *testing.T) {
+	helpers := New(template.New("envp"))
+	actual := helpers.RandomString(32, "ab")
+	assert.Regexp(t, `^[ab]{32}$`, actual)
+}
+
+func TestRandomPasswordWith(t *testing.T) {
+	type TestStruct struct {
+		opts        []string
+		description string
+		match       []string
+		reject      string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			opts:        []string{"symbols", "min-symbols=4"},
+			description: "it adds symbols with a minimum",
+			match:       []string{`([^a-zA-Z0-9].*){4}`},
+		},
+		TestStruct{
+			opts:        []string{"no-ambiguous"},
+			description: "it drops ambiguous characters",
+			reject:      `[0O1lI|]`,
+		},
+		TestStruct{
+			opts:        []string{"min-upper=3", "min-digits=3", "min-lower=3"},
+			description: "it guarantees minimums",
+			match:       []string{`([A-Z].*){3}`, `([0-9].*){3}`, `([a-z].*){3}`},
+			reject:      `[^a-zA-Z0-9]`,
+		},
+	} {
+		helpers := New(template.New("envp"))
+		for i := 0; i < 25; i++ {
+			actual := helpers.RandomPasswordWith(9, test.opts...)
+			assert.Equal(t, 9, len(actual), test.description)
+			for _, v := range test.match {
+				assert.Regexp(t, v, actual, test.description)
+			}
+
+			if test.reject != "" {
+				assert.NotRegexp(t, test.reject, actual,
+					test.description)
+			}
+		}
+	}
+}
+
+func TestRandomHex(t *testing.T) {
+	helpers := New(template.New("envp"))
+	assert.Regexp(t, `^[0-9a-f]{32}$`, helpers.RandomHex(16))
+}
+
+func TestRandomBase64(t *testing.T) {
+	helpers := New(template.New("envp"))
+	assert.Equal(t, 24, len(helpers.RandomBase64(16)))
+}
+
+func TestRandomInt(t *testing.T) {
+	helpers := New(template.New("envp"))
+	for i := 0; i < 100; i++ {
+		actual := helpers.RandomInt(-2, 3)
+		assert.True(t, actual >= -2 && actual < 3)
+	}
+
+	assert.NotPanics(t, func() {
+		helpers.RandomInt(math.MinInt64, math.MaxInt64)
+	}, "it works with spans wider than an int64")
+
+	assert.Equal(t, int64(math.MaxInt64-1), helpers.RandomInt(math.MaxInt64-1, math.MaxInt64))
+	assert.Equal(t, int64(math.MinInt64), helpers.RandomInt(math.MinInt64, math.MinInt64+1))
+}
+
+func TestUUID(t *testing.T) {
+	helpers := New(template.New("envp"))
+	re := `^[0-9a-f]{8}-[0-9a-f]{4}-%s[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`
+	assert.Regexp(t, strings.Replace(re, "%s", "4", 1), helpers.UUID())
+	first, second := helpers.UUIDv7(), helpers.UUIDv7()
+	assert.Regexp(t, regexp.MustCompile(strings.Replace(re, "%s", "7", 1)), first)
+	assert.True(t, first[:8] <= second[:8], "it's time ordered")
+
+	helpers.Time = time.Date(2018, 12, 25, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "0167e2a9-3800", helpers.UUIDv7()[:13],
+		"it uses the time of the render")
+}
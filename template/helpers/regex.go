@@ -0,0 +1,80 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// regex compiles a pattern once, and holds it so
+// that a range over a list doesn't compile it per item.
+func (h *Helpers) regex(pattern string) *regexp.Regexp {
+	if re, ok := h.regexps[pattern]; ok {
+		return re
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if h.regexps == nil {
+		h.regexps = map[string]*regexp.Regexp{}
+	}
+
+	h.regexps[pattern] = re
+	return re
+}
+
+// RegexMatch tells you if the string matches
+func (h *Helpers) RegexMatch(pattern, s string) bool {
+	return h.regex(pattern).MatchString(s)
+}
+
+// RegexFind gives you the first match, or ""
+func (h *Helpers) RegexFind(pattern, s string) string {
+	return h.regex(pattern).FindString(s)
+}
+
+// RegexFindAll gives you all of the matches
+func (h *Helpers) RegexFindAll(pattern, s string) []string {
+	out := h.regex(pattern).FindAllString(s, -1)
+	if out == nil {
+		return []string{}
+	}
+
+	return out
+}
+
+// RegexReplaceAll replaces all of the matches, you
+// can use $1, or ${name} for the groups in replacement.
+func (h *Helpers) RegexReplaceAll(pattern, replacement, s string) string {
+	return h.regex(pattern).ReplaceAllString(s, replacement)
+}
+
+// RegexSplit splits a string on the matches
+func (h *Helpers) RegexSplit(pattern, s string) []string {
+	return h.regex(pattern).Split(s, -1)
+}
+
+// RegexCapture gives you the named groups of the first
+// match as a map, it's empty if there is no match.
+func (h *Helpers) RegexCapture(pattern, s string) map[string]interface{} {
+	re, out := h.regex(pattern), map[string]interface{}{}
+	match := re.FindStringSubmatch(s)
+	if match == nil {
+		return out
+	}
+
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			out[name] = match[i]
+		}
+	}
+
+	return out
+}
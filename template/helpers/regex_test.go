@@ -0,0 +1,66 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegex(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "true false",
+			description: "regexMatch matches",
+			input:       `{{ "db:5432" | regexMatch ":\\d+$" }} {{ "db" | regexMatch ":\\d+$" }}`,
+		},
+		TestStruct{
+			expected:    "5432 []",
+			description: "regexFind finds the first",
+			input:       `{{ "db:5432:1" | regexFind "\\d+" }} [{{ "db" | regexFind "\\d+" }}]`,
+		},
+		TestStruct{
+			expected:    `["5432","1"] []`,
+			description: "regexFindAll finds them all",
+			input:       `{{ "db:5432:1" | regexFindAll "\\d+" | toJson }} {{ "db" | regexFindAll "\\d+" | toJson }}`,
+		},
+		TestStruct{
+			expected:    "5432@db",
+			description: "regexReplaceAll expands groups",
+			input:       `{{ "db:5432" | regexReplaceAll "(\\w+):(?P<port>\\d+)" "${port}@$1" }}`,
+		},
+		TestStruct{
+			expected:    `["a","b","c"]`,
+			description: "regexSplit splits",
+			input:       `{{ "a, b;c" | regexSplit "[,;]\\s*" | toJson }}`,
+		},
+		TestStruct{
+			expected:    `{"image":"nginx","tag":"1.15"}`,
+			description: "regexCapture gives you the named groups",
+			input:       `{{ "nginx:1.15" | regexCapture "^(?P<image>[^:]+)(?::(?P<tag>.+))?$" | toJson }}`,
+		},
+		TestStruct{
+			expected:    `{}`,
+			description: "regexCapture is empty without a match",
+			input:       `{{ "" | regexCapture "^(?P<image>.+)$" | toJson }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
+
+func TestRegexCache(t *testing.T) {
+	helpers := New(template.New("envp"))
+	assert.True(t, helpers.regex("a+") == helpers.regex("a+"),
+		"it compiles a pattern once")
+}
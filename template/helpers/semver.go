@@ -0,0 +1,284 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	semverRegex = regexp.MustCompile(`^v?(\d+)(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?` +
+		`(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+	constraintRegex = regexp.MustCompile(`(==|=|!=|>=|<=|>|<|~|\^)?\s*(v?[0-9][^\s,]*)`)
+)
+
+/**
+ * Semver is a semantic version, like 1.2.3-rc.1+b2
+ * you can pull it apart in templates, like .Major
+ */
+type Semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Metadata   string
+
+	// parts is how many of major, minor, and patch
+	// were given, so a constraint of 1.2 is 1.2.x
+	parts int
+}
+
+// ParseSemver parses a version, a "v" in front is
+// fine, so is leaving out the minor, or the patch.
+func ParseSemver(s string) (*Semver, error) {
+	m := semverRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("invalid version %q", s)
+	}
+
+	out := &Semver{Prerelease: m[4], Metadata: m[5]}
+	for i, p := range []*int{&out.Major, &out.Minor, &out.Patch} {
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			break
+		}
+
+		*p = n
+		out.parts++
+	}
+
+	return out, nil
+}
+
+// String gives you the version back as a string
+func (s *Semver) String() string {
+	out := fmt.Sprintf("%d.%d.%d", s.Major, s.Minor, s.Patch)
+	if s.Prerelease != "" {
+		out += "-" + s.Prerelease
+	}
+
+	if s.Metadata != "" {
+		out += "+" + s.Metadata
+	}
+
+	return out
+}
+
+// comparePrerelease compares pre-releases the way
+// semver.org says, numbers are less than words, and
+// having no pre-release is more than having one.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	} else if a == "" {
+		return 1
+	} else if b == "" {
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aerr := strconv.Atoi(as[i])
+		bn, berr := strconv.Atoi(bs[i])
+		switch {
+		case aerr == nil && berr == nil && an != bn:
+			return compareInt(an, bn)
+		case aerr == nil && berr != nil:
+			return -1
+		case aerr != nil && berr == nil:
+			return 1
+		case as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+
+	return compareInt(len(as), len(bs))
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+// Compare gives you -1, 0, or 1, metadata is ignored
+func (s *Semver) Compare(o *Semver) int {
+	for _, c := range [][2]int{{s.Major, o.Major}, {s.Minor, o.Minor}, {s.Patch, o.Patch}} {
+		if c[0] != c[1] {
+			return compareInt(c[0], c[1])
+		}
+	}
+
+	return comparePrerelease(s.Prerelease, o.Prerelease)
+}
+
+// next gives you the version after the partial
+// version, so 1.2 is 1.3.0, and 1 is 2.0.0
+func (s *Semver) next(parts int) *Semver {
+	switch parts {
+	case 1:
+		return &Semver{Major: s.Major + 1}
+	case 2:
+		return &Semver{Major: s.Major, Minor: s.Minor + 1}
+	}
+
+	return &Semver{Major: s.Major, Minor: s.Minor, Patch: s.Patch + 1}
+}
+
+// below is a check for v < upper, the pre-releases
+// of upper are out too, so ~1.2 doesn't match 1.3.0-rc
+func below(upper *Semver) func(*Semver) bool {
+	return func(v *Semver) bool {
+		core := &Semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+		return core.Compare(upper) < 0
+	}
+}
+
+// between is a check for lower <= v < upper
+func between(lower, upper *Semver) func(*Semver) bool {
+	return func(v *Semver) bool {
+		return v.Compare(lower) >= 0 && below(upper)(v)
+	}
+}
+
+// constraint turns an operator, and a (maybe partial)
+// version into a check, 1.2 is 1.2.x, ~ allows patches
+// and ^ allows anything that doesn't change the left
+// most number that isn't zero, like npm, and cargo.
+func constraint(op string, c *Semver) func(*Semver) bool {
+	partial := c.parts < 3
+	switch op {
+	case "", "=", "==":
+		if partial {
+			return between(c, c.next(c.parts))
+		}
+
+		return func(v *Semver) bool { return v.Compare(c) == 0 }
+	case "!=":
+		check := constraint("=", c)
+		return func(v *Semver) bool { return !check(v) }
+	case ">":
+		if partial {
+			upper := c.next(c.parts)
+			return func(v *Semver) bool { return v.Compare(upper) >= 0 }
+		}
+
+		return func(v *Semver) bool { return v.Compare(c) > 0 }
+	case ">=":
+		return func(v *Semver) bool { return v.Compare(c) >= 0 }
+	case "<":
+		return func(v *Semver) bool { return v.Compare(c) < 0 }
+	case "<=":
+		if partial {
+			return below(c.next(c.parts))
+		}
+
+		return func(v *Semver) bool { return v.Compare(c) <= 0 }
+	case "~":
+		if c.parts == 1 {
+			return between(c, c.next(1))
+		}
+
+		return between(c, c.next(2))
+	}
+
+	// ^
+	switch {
+	case c.Major > 0 || c.parts == 1:
+		return between(c, c.next(1))
+	case c.Minor > 0 || c.parts == 2:
+		return between(c, c.next(2))
+	}
+
+	return between(c, c.next(3))
+}
+
+// SemverMatches checks a version against constraints
+// like ">=1.2 <2", "~1.2.3", or "^1.2 || ^2", spaces,
+// or commas are "and", and || is "or".
+func SemverMatches(constraints string, v *Semver) (bool, error) {
+	for _, group := range strings.Split(constraints, "||") {
+		matches := constraintRegex.FindAllStringSubmatch(group, -1)
+		if len(matches) == 0 {
+			return false, fmt.Errorf("invalid constraint %q", constraints)
+		}
+
+		ok := true
+		for _, m := range matches {
+			c, err := ParseSemver(m[2])
+			if err != nil {
+				return false, err
+			}
+
+			if !constraint(m[1], c)(v) {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Semver parses a version, so you can use .Major
+func (h *Helpers) Semver(s string) *Semver {
+	v, err := ParseSemver(s)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return v
+}
+
+// SemverCompare checks a version against constraints
+// like ">=1.2 <2", the version is last for pipelines.
+func (h *Helpers) SemverCompare(constraints, s string) bool {
+	ok, err := SemverMatches(constraints, h.Semver(s))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return ok
+}
+
+// RequireEnvp stops the render if the version of envp
+// doesn't match the constraints, like ">=0.5", so that
+// a template can say it needs a newer envp.
+func (h *Helpers) RequireEnvp(constraints string) (string, error) {
+	if h.Version == "" {
+		logrus.Warnf("unknown envp version, skipping requireEnvp %q", constraints)
+		return "", nil
+	}
+
+	v, err := ParseSemver(h.Version)
+	if err != nil {
+		return "", err
+	}
+
+	ok, err := SemverMatches(constraints, v)
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		return "", fmt.Errorf("this template needs envp %s, but you have %s, "+
+			"please upgrade envp", constraints, h.Version)
+	}
+
+	return "", nil
+}
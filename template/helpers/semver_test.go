@@ -0,0 +1,224 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSemver(t *testing.T) {
+	v, err := ParseSemver("v1.2.3-rc.1+b2")
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, v.Major)
+		assert.Equal(t, 2, v.Minor)
+		assert.Equal(t, 3, v.Patch)
+		assert.Equal(t, "rc.1", v.Prerelease)
+		assert.Equal(t, "b2", v.Metadata)
+		assert.Equal(t, "1.2.3-rc.1+b2", v.String())
+	}
+
+	_, err = ParseSemver("latest")
+	assert.Error(t, err)
+}
+
+func TestSemverMatches(t *testing.T) {
+	type TestStruct struct {
+		expected    bool
+		constraints string
+		version     string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    true,
+			constraints: ">=1.2 <2",
+			version:     "1.5.0",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: ">=1.2 <2",
+			version:     "2.0.0",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: ">=1.2 <2",
+			version:     "1.1.9",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: ">=1.2, <2",
+			version:     "1.2.0",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "1.2",
+			version:     "1.2.9",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "1.2.x",
+			version:     "1.2.9",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "1.2",
+			version:     "1.3.0",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "=1.2.3",
+			version:     "1.2.3+b1",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "=1.2.3",
+			version:     "1.2.3-rc.1",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "!=1.2.3",
+			version:     "1.2.4",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: ">1.2",
+			version:     "1.3.0",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: ">1.2",
+			version:     "1.2.9",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "<=1.2",
+			version:     "1.2.9",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "<=1.2",
+			version:     "1.3.0",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "~1.2.3",
+			version:     "1.2.9",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "~1.2.3",
+			version:     "1.3.0",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "~1.2.3",
+			version:     "1.3.0-rc.1",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "~1",
+			version:     "1.9.0",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "^1.2.3",
+			version:     "1.9.0",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "^1.2.3",
+			version:     "2.0.0",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "^0.2.3",
+			version:     "0.2.9",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "^0.2.3",
+			version:     "0.3.0",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "^0.0.3",
+			version:     "0.0.4",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: "^1 || ^3",
+			version:     "3.1.0",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: "^1 || ^3",
+			version:     "2.1.0",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: ">=1.0.0-alpha",
+			version:     "1.0.0-alpha.1",
+		},
+		TestStruct{
+			expected:    false,
+			constraints: ">=1.0.0-alpha.beta",
+			version:     "1.0.0-alpha.1",
+		},
+		TestStruct{
+			expected:    true,
+			constraints: ">=1.0.0-rc.2",
+			version:     "1.0.0-rc.10",
+		},
+	} {
+		v, _ := ParseSemver(test.version)
+		actual, err := SemverMatches(test.constraints, v)
+		if assert.Nil(t, err) {
+			assert.Equal(t, test.expected, actual,
+				test.constraints+" "+test.version)
+		}
+	}
+}
+
+func TestSemverHelpers(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "1 2 true",
+			description: "semver, and semverCompare",
+			input:       `{{ (semver "v1.2.3").Major }} {{ (semver "1.2").Minor }} {{ "1.2.3" | semverCompare ">=1.2 <2" }}`,
+		},
+		TestStruct{
+			expected:    "ok",
+			description: "requireEnvp allows matching versions",
+			input:       `{{ requireEnvp ">=0.5" }}ok`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description, func(h *Helpers) {
+			h.Version = "v0.5.0"
+		})
+	}
+}
+
+func TestRequireEnvp(t *testing.T) {
+	tpl := template.New("envp")
+	h := New(tpl)
+	h.Version = "v0.5.0"
+
+	var out strings.Builder
+	template.Must(tpl.Parse(`{{ requireEnvp ">=1.0" }}ok`))
+	err := tpl.Execute(&out, nil)
+	if assert.Error(t, err, "it stops old versions") {
+		assert.Contains(t, err.Error(), "needs envp >=1.0, but you have v0.5.0")
+	}
+}
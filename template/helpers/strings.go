@@ -0,0 +1,216 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The string helpers take the string last, so that
+// they work in pipelines, like `env "x" | trimPrefix "y"`
+
+// Upper upper cases a string
+func (h *Helpers) Upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+// Lower lower cases a string
+func (h *Helpers) Lower(s string) string {
+	return strings.ToLower(s)
+}
+
+// Title upper cases the first letter of each word
+func (h *Helpers) Title(s string) string {
+	prev, out := ' ', []rune(s)
+	for i, r := range out {
+		if unicode.IsSpace(prev) {
+			out[i] = unicode.ToTitle(r)
+		}
+
+		prev = r
+	}
+
+	return string(out)
+}
+
+// Trim removes the space around a string
+func (h *Helpers) Trim(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// TrimPrefix removes the prefix from a string
+func (h *Helpers) TrimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+// TrimSuffix removes the suffix from a string
+func (h *Helpers) TrimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+// Replace replaces all of old with new in a string
+func (h *Helpers) Replace(old, new, s string) string {
+	return strings.Replace(s, old, new, -1)
+}
+
+// Contains tells you if a string has the substring
+func (h *Helpers) Contains(substr, s string) bool {
+	return strings.Contains(s, substr)
+}
+
+// HasPrefix tells you if a string starts with prefix
+func (h *Helpers) HasPrefix(prefix, s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+// HasSuffix tells you if a string ends with suffix
+func (h *Helpers) HasSuffix(suffix, s string) bool {
+	return strings.HasSuffix(s, suffix)
+}
+
+// Join joins any list (like from split, or list)
+func (h *Helpers) Join(sep string, l interface{}) string {
+	list := toList(l)
+	out := make([]string, len(list))
+	for i, v := range list {
+		out[i] = fmt.Sprint(v)
+	}
+
+	return strings.Join(out, sep)
+}
+
+// Repeat repeats a string n times
+func (h *Helpers) Repeat(n int, s string) string {
+	if n < 0 {
+		return ""
+	}
+
+	return strings.Repeat(s, n)
+}
+
+// Substr gives you the characters from start to end,
+// a negative end means to the end of the string.
+func (h *Helpers) Substr(start, end int, s string) string {
+	runes := []rune(s)
+	if end < 0 || end > len(runes) {
+		end = len(runes)
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if start >= end {
+		return ""
+	}
+
+	return string(runes[start:end])
+}
+
+// Truncate cuts a string down to n characters
+func (h *Helpers) Truncate(n int, s string) string {
+	return h.Substr(0, n, s)
+}
+
+// Wrap wraps a string at width, on word boundaries,
+// words that are longer than the width are left alone.
+func (h *Helpers) Wrap(width int, s string) string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		current := ""
+		for _, word := range strings.Fields(line) {
+			switch {
+			case current == "":
+				current = word
+			case len([]rune(current))+1+len([]rune(word)) > width:
+				out = append(out, current)
+				current = word
+			default:
+				current += " " + word
+			}
+		}
+
+		out = append(out, current)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// words splits a string into lower case words, on
+// anything that isn't a letter, or a digit, and where
+// the case changes, so HTTPServer is http, and server.
+func words(s string) []string {
+	var (
+		out  []string
+		word []rune
+	)
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				out, word = append(out, string(word)), nil
+			}
+
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				out, word = append(out, string(word)), nil
+			}
+		}
+
+		word = append(word, unicode.ToLower(r))
+	}
+
+	if len(word) > 0 {
+		out = append(out, string(word))
+	}
+
+	return out
+}
+
+// Camelcase turns a string into camelCase
+func (h *Helpers) Camelcase(s string) string {
+	w := words(s)
+	for i := 1; i < len(w); i++ {
+		r := []rune(w[i])
+		r[0] = unicode.ToUpper(r[0])
+		w[i] = string(r)
+	}
+
+	return strings.Join(w, "")
+}
+
+// Snakecase turns a string into snake_case
+func (h *Helpers) Snakecase(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+// Kebabcase turns a string into kebab-case
+func (h *Helpers) Kebabcase(s string) string {
+	return strings.Join(words(s), "-")
+}
+
+// Nospace removes all of the space from a string
+func (h *Helpers) Nospace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+
+		return r
+	}, s)
+}
+
+// SplitN splits a string into at most n parts
+func (h *Helpers) SplitN(sep string, n int, s string) []string {
+	return strings.SplitN(s, sep, n)
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"testing"
+)
+
+func TestStrings(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			expected:    "HELLO hello Hello World",
+			description: "upper, lower, and title",
+			input:       `{{ "hello" | upper }} {{ "HELLO" | lower }} {{ "hello world" | title }}`,
+		},
+		TestStruct{
+			expected:    "[a] b a",
+			description: "trim, trimPrefix, and trimSuffix",
+			input:       `[{{ " a " | trim }}] {{ "ab" | trimPrefix "a" }} {{ "ab" | trimSuffix "b" }}`,
+		},
+		TestStruct{
+			expected:    "a-b-c",
+			description: "replace replaces all",
+			input:       `{{ "a.b.c" | replace "." "-" }}`,
+		},
+		TestStruct{
+			expected:    "true true true false",
+			description: "contains, hasPrefix, and hasSuffix",
+			input:       `{{ "abc" | contains "b" }} {{ "abc" | hasPrefix "a" }} {{ "abc" | hasSuffix "c" }} {{ "abc" | contains "d" }}`,
+		},
+		TestStruct{
+			expected:    "a,1,b",
+			description: "join joins any list",
+			input:       `{{ list "a" 1 "b" | join "," }}`,
+		},
+		TestStruct{
+			expected:    "ababab",
+			description: "repeat repeats",
+			input:       `{{ "ab" | repeat 3 }}`,
+		},
+		TestStruct{
+			expected:    "éf ab cdéf",
+			description: "substr, and truncate work on characters",
+			input:       `{{ "abcdéf" | substr 4 -1 }} {{ "abcdéf" | truncate 2 }} {{ "abcdéf" | substr 2 100 }}`,
+		},
+		TestStruct{
+			expected:    "the quick\nbrown fox\njumps",
+			description: "wrap wraps on words",
+			input:       `{{ "the quick brown fox jumps" | wrap 10 }}`,
+		},
+		TestStruct{
+			expected:    "httpServerUrl http_server_url http-server-url",
+			description: "camelcase, snakecase, and kebabcase",
+			input:       `{{ "HTTPServer_URL" | camelcase }} {{ "httpServer url" | snakecase }} {{ "Http-Server_URL" | kebabcase }}`,
+		},
+		TestStruct{
+			expected:    "db_host2_port",
+			description: "snakecase splits after digits",
+			input:       `{{ "dbHost2Port" | snakecase }}`,
+		},
+		TestStruct{
+			expected:    "abc",
+			description: "nospace removes all space",
+			input:       "{{ \" a\\tb\\nc \" | nospace }}",
+		},
+		TestStruct{
+			expected:    `["host","8080:80"]`,
+			description: "splitN splits into n",
+			input:       `{{ "host:8080:80" | splitN ":" 2 | toJson }}`,
+		},
+	} {
+		assertRender(t, test.input, test.expected, test.description)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/envygeeks/envp/state"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// CertKind is the kind we give to certs
+	// that we persist in the state for you.
+	CertKind = "cert"
+
+	certPrefix = "tls/"
+	day        = 24 * time.Hour
+)
+
+// Cert is a PEM encoded key, and certificate, use
+// .Cert, and .Key, or write both out with .Save
+type Cert struct {
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+
+	h *Helpers
+}
+
+// Save writes the cert, and the key to separate files
+// inside of --allow-write, the key is 0600, it returns ""
+// so you can do it inline within your template w/o output.
+func (c *Cert) Save(cert, key string) string {
+	if c.h == nil {
+		logrus.Fatalln("unable to save a cert that wasn't generated")
+	}
+
+	for _, v := range []struct {
+		path string
+		body string
+		mode os.FileMode
+	}{
+		{path: cert, body: c.Cert, mode: 0644},
+		{path: key, body: c.Key, mode: 0600},
+	} {
+		path := c.h.writable(v.path)
+		logrus.Debugf("writing %s", path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			logrus.Fatalln(err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(v.body), v.mode); err != nil {
+			logrus.Fatalln(err)
+		}
+	}
+
+	return ""
+}
+
+// parse parses the cert, and key back out of PEM
+func (c *Cert) parse() (*x509.Certificate, crypto.Signer, error) {
+	cblock, _ := pem.Decode([]byte(c.Cert))
+	kblock, _ := pem.Decode([]byte(c.Key))
+	if cblock == nil || kblock == nil {
+		return nil, nil, errors.New("unable to decode the certificate")
+	}
+
+	cert, err := x509.ParseCertificate(cblock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(kblock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key.(crypto.Signer), nil
+}
+
+// privateKey generates a key for the algorithm,
+// rsa (2048), rsa4096, ecdsa (P-256), or ed25519
+func privateKey(algo string) crypto.Signer {
+	var (
+		key crypto.Signer
+		err error
+	)
+
+	switch algo {
+	case "rsa":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "rsa4096":
+		key, err = rsa.GenerateKey(rand.Reader, 4096)
+	case "ecdsa":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		logrus.Fatalf("unknown key algorithm %s", algo)
+	}
+
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return key
+}
+
+// certTemplate builds the basics of a certificate,
+// it's valid from now (so --now is respected) for days
+func certTemplate(now time.Time, name string, days int, sans []string) *x509.Certificate {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	cert := &x509.Certificate{
+		SerialNumber:          serial,
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(time.Duration(days) * day),
+		Subject:               pkix.Name{CommonName: name},
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	for _, v := range sans {
+		if ip := net.ParseIP(v); ip != nil {
+			cert.IPAddresses = append(cert.IPAddresses, ip)
+			continue
+		}
+
+		cert.DNSNames = append(cert.DNSNames, v)
+	}
+
+	return cert
+}
+
+// sign signs the template, and PEM encodes it all
+func sign(tpl, parent *x509.Certificate, key, signer crypto.Signer) *Cert {
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		tpl.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parent, key.Public(), signer)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	return &Cert{
+		Cert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		Key:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+	}
+}
+
+// persistCert pulls a cert out of the state, or it
+// generates it, it's regenerated once it's expired, or
+// once the params (like the SANs, or the CA) change.
+func (h *Helpers) persistCert(name, params string, gen func() *Cert) *Cert {
+	valid := func(s *state.Secret) bool {
+		c := &Cert{}
+		if err := json.Unmarshal([]byte(s.Value), c); err != nil {
+			return false
+		}
+
+		cert, _, err := c.parse()
+		return err == nil && h.Now().Before(cert.NotAfter)
+	}
+
+	c := &Cert{h: h}
+	h.persistJSON(certPrefix+name, CertKind, params, c, valid, func() interface{} {
+		return gen()
+	})
+
+	return c
+}
+
+// GenerateCA generates a certificate authority that
+// you can hand to signCert, it's persisted by name
+func (h *Helpers) GenerateCA(name, algo string, days int) *Cert {
+	params := fingerprint("ca", algo, days)
+	return h.persistCert(name, params, func() *Cert {
+		key, tpl := privateKey(algo), certTemplate(h.Now(), name, days, nil)
+		tpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+		tpl.ExtKeyUsage, tpl.IsCA = nil, true
+		return sign(tpl, tpl, key, key)
+	})
+}
+
+// SelfSignedCert generates a self-signed certificate
+// for the SANs (DNS names, or IPs) it's persisted by name
+func (h *Helpers) SelfSignedCert(name, algo string, days int, sans ...string) *Cert {
+	params := fingerprint("self", algo, days, sans)
+	return h.persistCert(name, params, func() *Cert {
+		key, tpl := privateKey(algo), certTemplate(h.Now(), name, days, sans)
+		return sign(tpl, tpl, key, key)
+	})
+}
+
+// SignCert generates a certificate signed by your CA
+// for the SANs (DNS names, or IPs) it's persisted by name,
+// and it's regenerated if the CA is, so the chain works.
+func (h *Helpers) SignCert(name string, ca *Cert, algo string, days int, sans ...string) *Cert {
+	parent, signer, err := ca.parse()
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	params := fingerprint("signed", algo, days, sans, parent.SerialNumber.String())
+	return h.persistCert(name, params, func() *Cert {
+		key, tpl := privateKey(algo), certTemplate(h.Now(), name, days, sans)
+		return sign(tpl, parent, key, signer)
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package helpers
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tlsHelpers(t *testing.T) (*Helpers, string) {
+	dir, err := ioutil.TempDir("", "test-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	helpers := New(template.New("envp"))
+	helpers.StateFile = filepath.Join(dir, "state.json")
+	return helpers, dir
+}
+
+func TestSelfSignedCert(t *testing.T) {
+	helpers, dir := tlsHelpers(t)
+	defer os.RemoveAll(dir)
+
+	for _, algo := range []string{"rsa", "ecdsa", "ed25519"} {
+		c := helpers.SelfSignedCert("web-"+algo, algo, 30, "localhost", "127.0.0.1")
+		cert, _, err := c.parse()
+		if assert.Nil(t, err, algo) {
+			assert.Equal(t, []string{"localhost"}, cert.DNSNames)
+			assert.Equal(t, "127.0.0.1", cert.IPAddresses[0].String())
+			assert.Nil(t, cert.VerifyHostname("localhost"))
+		}
+
+		again := helpers.SelfSignedCert("web-"+algo, algo, 30, "localhost", "127.0.0.1")
+		assert.Equal(t, c, again, "it's persisted")
+
+		changed := helpers.SelfSignedCert("web-"+algo, algo, 30, "localhost")
+		assert.NotEqual(t, c.Cert, changed.Cert, "it's regenerated when the SANs change")
+		cert, _, err = changed.parse()
+		if assert.Nil(t, err, algo) {
+			assert.Empty(t, cert.IPAddresses)
+		}
+	}
+}
+
+func TestSignCert(t *testing.T) {
+	helpers, dir := tlsHelpers(t)
+	defer os.RemoveAll(dir)
+
+	ca := helpers.GenerateCA("ca", "ecdsa", 365)
+	c := helpers.SignCert("web", ca, "ecdsa", 30, "example.com")
+	cacert, _, _ := ca.parse()
+	cert, _, err := c.parse()
+	if assert.Nil(t, err) {
+		assert.True(t, cacert.IsCA)
+		pool := x509.NewCertPool()
+		pool.AddCert(cacert)
+		_, err := cert.Verify(x509.VerifyOptions{
+			DNSName: "example.com",
+			Roots:   pool,
+		})
+
+		assert.Nil(t, err)
+	}
+
+	again := helpers.SignCert("web", ca, "ecdsa", 30, "example.com")
+	assert.Equal(t, c, again, "it's persisted")
+
+	ca = helpers.GenerateCA("ca", "ecdsa", 30)
+	again = helpers.SignCert("web", ca, "ecdsa", 30, "example.com")
+	assert.NotEqual(t, c.Cert, again.Cert, "it's regenerated with the CA")
+	cacert, _, _ = ca.parse()
+	cert, _, err = again.parse()
+	if assert.Nil(t, err) {
+		pool := x509.NewCertPool()
+		pool.AddCert(cacert)
+		_, err := cert.Verify(x509.VerifyOptions{
+			DNSName: "example.com",
+			Roots:   pool,
+		})
+
+		assert.Nil(t, err)
+	}
+}
+
+func TestCertNow(t *testing.T) {
+	helpers, dir := tlsHelpers(t)
+	defer os.RemoveAll(dir)
+
+	helpers.Time = time.Date(2018, 12, 25, 0, 0, 0, 0, time.UTC)
+	cert, _, err := helpers.SelfSignedCert("web", "ecdsa", 30, "localhost").parse()
+	if assert.Nil(t, err) {
+		assert.Equal(t, helpers.Time.Add(30*day), cert.NotAfter.UTC(),
+			"it uses the time of the render")
+	}
+}
+
+func TestCertSave(t *testing.T) {
+	helpers, dir := tlsHelpers(t)
+	defer os.RemoveAll(dir)
+
+	helpers.AllowWrite = []string{filepath.Join(dir, "ssl")}
+	c := helpers.SelfSignedCert("web", "ecdsa", 1, "localhost")
+	certPath, keyPath := filepath.Join(dir, "ssl", "cert.pem"), filepath.Join(dir, "ssl", "key.pem")
+	assert.Equal(t, "", c.Save(certPath, keyPath))
+	_, ok := jailed(helpers.AllowWrite, filepath.Join(dir, "cert.pem"))
+	assert.False(t, ok, "it only writes inside of --allow-write")
+
+	b, _ := ioutil.ReadFile(certPath)
+	assert.Equal(t, c.Cert, string(b))
+	finfo, err := os.Stat(keyPath)
+	if assert.Nil(t, err) {
+		assert.Equal(t, os.FileMode(0600), finfo.Mode().Perm())
+	}
+}
@@ -0,0 +1,483 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+/**
+ * schema checks data against a JSON Schema, it's
+ * the subset that you need for config files: type,
+ * properties, required, additionalProperties, items,
+ * enum, const, the min/max family, pattern, allOf,
+ * anyOf, oneOf, not, and local $ref's into the doc,
+ * anything else is an error, so nothing's skipped.
+ */
+type schema struct {
+	root map[string]interface{}
+}
+
+var (
+	// keywords are what we check, and what we can
+	// ignore because they don't say what's valid.
+	keywords = map[string]bool{
+		"type": true, "properties": true, "required": true, "additionalProperties": true,
+		"items": true, "enum": true, "const": true, "minLength": true, "maxLength": true,
+		"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true,
+		"multipleOf": true, "minItems": true, "maxItems": true, "uniqueItems": true,
+		"minProperties": true, "maxProperties": true, "pattern": true, "allOf": true,
+		"anyOf": true, "oneOf": true, "not": true, "$ref": true,
+
+		"$schema": true, "$id": true, "id": true, "$comment": true, "title": true,
+		"description": true, "default": true, "examples": true, "definitions": true,
+		"$defs": true, "readOnly": true, "writeOnly": true, "deprecated": true,
+	}
+)
+
+// ValidateSchema checks data against a JSON Schema
+func ValidateSchema(data interface{}, b []byte) error {
+	var root map[string]interface{}
+	if err := json.Unmarshal(b, &root); err != nil {
+		return fmt.Errorf("invalid schema: %s", err)
+	}
+
+	if err := supported(root, ""); err != nil {
+		return fmt.Errorf("invalid schema: %s", err)
+	}
+
+	s := &schema{root: root}
+	return s.check(root, data, "")
+}
+
+// supported walks the whole schema, and errors on the
+// keywords that we don't know, so a schema that should
+// fail never quietly passes, even where data doesn't go.
+func supported(v interface{}, path string) error {
+	sc, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var unknown []string
+	for k := range sc {
+		if !keywords[k] {
+			unknown = append(unknown, k)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return errorf(path, "unsupported keyword %s", strings.Join(unknown, ", "))
+	}
+
+	for _, k := range []string{"additionalProperties", "items", "not"} {
+		if err := supported(sc[k], path+"/"+k); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+		list, _ := sc[k].([]interface{})
+		for i, vv := range list {
+			if err := supported(vv, fmt.Sprintf("%s/%s/%d", path, k, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, k := range []string{"properties", "definitions", "$defs"} {
+		m, _ := sc[k].(map[string]interface{})
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			if err := supported(m[name], path+"/"+k+"/"+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// number gives you a float64 for any number
+func number(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	}
+
+	return 0, false
+}
+
+// typeOf gives you the JSON Schema type of a value
+func typeOf(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		if n, ok := number(t); ok {
+			if n == math.Trunc(n) {
+				return "integer"
+			}
+
+			return "number"
+		}
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+// hasType tells you if a value is one of the types
+func hasType(v interface{}, types interface{}) bool {
+	var list []interface{}
+	switch t := types.(type) {
+	case string:
+		list = []interface{}{t}
+	case []interface{}:
+		list = t
+	}
+
+	actual := typeOf(v)
+	for _, want := range list {
+		if want == actual || (want == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equal compares values, numbers are compared by value
+// so that YAML's int, and JSON's float64 are the same.
+func equal(a, b interface{}) bool {
+	an, aok := number(a)
+	bn, bok := number(b)
+	if aok && bok {
+		return an == bn
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// ref resolves a local $ref, like #/definitions/name
+func (s *schema) ref(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only local $ref's are supported, not %s", ref)
+	}
+
+	var cur interface{} = s.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if part == "" {
+			continue
+		}
+
+		part = strings.Replace(part, "~1", "/", -1)
+		part = strings.Replace(part, "~0", "~", -1)
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unable to resolve $ref %s", ref)
+		}
+
+		if cur, ok = m[part]; !ok {
+			return nil, fmt.Errorf("unable to resolve $ref %s", ref)
+		}
+	}
+
+	out, ok := cur.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to resolve $ref %s", ref)
+	}
+
+	return out, nil
+}
+
+// sub turns a schema value into a schema, true and
+// false are schemas that allow, and deny everything.
+func sub(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t
+	case bool:
+		if !t {
+			return map[string]interface{}{"not": map[string]interface{}{}}
+		}
+	}
+
+	return map[string]interface{}{}
+}
+
+// errorf makes an error at a JSON pointer into the data
+func errorf(path, format string, args ...interface{}) error {
+	if path == "" {
+		path = "/"
+	}
+
+	return fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+}
+
+// check checks a value, path is where it's at as a
+// JSON pointer, so you know what's wrong and where.
+func (s *schema) check(sc map[string]interface{}, v interface{}, path string) error {
+	if ref, ok := sc["$ref"].(string); ok {
+		resolved, err := s.ref(ref)
+		if err != nil {
+			return err
+		}
+
+		return s.check(resolved, v, path)
+	}
+
+	if types, ok := sc["type"]; ok && !hasType(v, types) {
+		return errorf(path, "expected %v, got %s", types, typeOf(v))
+	}
+
+	if enum, ok := sc["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return errorf(path, "%v is not one of %v", v, enum)
+		}
+	}
+
+	if c, ok := sc["const"]; ok && !equal(c, v) {
+		return errorf(path, "expected %v, got %v", c, v)
+	}
+
+	if err := s.checkCombinators(sc, v, path); err != nil {
+		return err
+	}
+
+	switch t := v.(type) {
+	case string:
+		return checkString(sc, t, path)
+	case map[string]interface{}:
+		return s.checkObject(sc, t, path)
+	case []interface{}:
+		return s.checkArray(sc, t, path)
+	}
+
+	if n, ok := number(v); ok {
+		return checkNumber(sc, n, path)
+	}
+
+	return nil
+}
+
+func (s *schema) checkCombinators(sc map[string]interface{}, v interface{}, path string) error {
+	if all, ok := sc["allOf"].([]interface{}); ok {
+		for _, a := range all {
+			if err := s.check(sub(a), v, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if anyOf, ok := sc["anyOf"].([]interface{}); ok {
+		var errs []string
+		for _, a := range anyOf {
+			err := s.check(sub(a), v, path)
+			if err == nil {
+				errs = nil
+				break
+			}
+
+			errs = append(errs, err.Error())
+		}
+
+		if errs != nil {
+			return errorf(path, "matches none of anyOf (%s)", strings.Join(errs, "; "))
+		}
+	}
+
+	if one, ok := sc["oneOf"].([]interface{}); ok {
+		matched := 0
+		for _, o := range one {
+			if s.check(sub(o), v, path) == nil {
+				matched++
+			}
+		}
+
+		if matched != 1 {
+			return errorf(path, "matches %d of oneOf, expected 1", matched)
+		}
+	}
+
+	if not, ok := sc["not"]; ok {
+		if s.check(sub(not), v, path) == nil {
+			return errorf(path, "must not match the schema in not")
+		}
+	}
+
+	return nil
+}
+
+func checkString(sc map[string]interface{}, v, path string) error {
+	length := float64(utf8.RuneCountInString(v))
+	if min, ok := number(sc["minLength"]); ok && length < min {
+		return errorf(path, "%q is shorter than %v", v, min)
+	}
+
+	if max, ok := number(sc["maxLength"]); ok && length > max {
+		return errorf(path, "%q is longer than %v", v, max)
+	}
+
+	if pattern, ok := sc["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return errorf(path, "invalid pattern %s", err)
+		}
+
+		if !re.MatchString(v) {
+			return errorf(path, "%q doesn't match %s", v, pattern)
+		}
+	}
+
+	return nil
+}
+
+func checkNumber(sc map[string]interface{}, v float64, path string) error {
+	if min, ok := number(sc["minimum"]); ok && v < min {
+		return errorf(path, "%v is less than %v", v, min)
+	}
+
+	if max, ok := number(sc["maximum"]); ok && v > max {
+		return errorf(path, "%v is more than %v", v, max)
+	}
+
+	if min, ok := number(sc["exclusiveMinimum"]); ok && v <= min {
+		return errorf(path, "%v is not more than %v", v, min)
+	}
+
+	if max, ok := number(sc["exclusiveMaximum"]); ok && v >= max {
+		return errorf(path, "%v is not less than %v", v, max)
+	}
+
+	if of, ok := number(sc["multipleOf"]); ok && of != 0 {
+		if q := v / of; q != math.Trunc(q) {
+			return errorf(path, "%v is not a multiple of %v", v, of)
+		}
+	}
+
+	return nil
+}
+
+func (s *schema) checkObject(sc map[string]interface{}, v map[string]interface{}, path string) error {
+	if required, ok := sc["required"].([]interface{}); ok {
+		for _, r := range required {
+			if _, ok := v[fmt.Sprint(r)]; !ok {
+				return errorf(path, "missing required property %v", r)
+			}
+		}
+	}
+
+	if min, ok := number(sc["minProperties"]); ok && float64(len(v)) < min {
+		return errorf(path, "has fewer than %v properties", min)
+	}
+
+	if max, ok := number(sc["maxProperties"]); ok && float64(len(v)) > max {
+		return errorf(path, "has more than %v properties", max)
+	}
+
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	props, _ := sc["properties"].(map[string]interface{})
+	additional, hasAdditional := sc["additionalProperties"]
+	for _, k := range keys {
+		p := path + "/" + strings.Replace(strings.Replace(k,
+			"~", "~0", -1), "/", "~1", -1)
+
+		if prop, ok := props[k]; ok {
+			if err := s.check(sub(prop), v[k], p); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				return errorf(path, "unexpected property %s", k)
+			}
+
+			if err := s.check(sub(additional), v[k], p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *schema) checkArray(sc map[string]interface{}, v []interface{}, path string) error {
+	if min, ok := number(sc["minItems"]); ok && float64(len(v)) < min {
+		return errorf(path, "has fewer than %v items", min)
+	}
+
+	if max, ok := number(sc["maxItems"]); ok && float64(len(v)) > max {
+		return errorf(path, "has more than %v items", max)
+	}
+
+	if unique, ok := sc["uniqueItems"].(bool); ok && unique {
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if equal(v[i], v[j]) {
+					return errorf(path, "has duplicate items at %d, and %d", i, j)
+				}
+			}
+		}
+	}
+
+	if items, ok := sc["items"]; ok {
+		if _, ok := items.([]interface{}); ok {
+			return errorf(path, "unsupported keyword items as an array (tuple)")
+		}
+
+		for i, item := range v {
+			if err := s.check(sub(items), item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchema(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		format      string
+		schema      string
+		input       string
+	}
+
+	schema := `{
+		"type": "object",
+		"required": ["name", "port"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"mode": {"enum": ["dev", "prod"]},
+			"hosts": {"type": "array", "items": {"$ref": "#/definitions/host"}}
+		},
+		"definitions": {
+			"host": {"type": "string", "pattern": "^[a-z.]+$"}
+		}
+	}`
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			description: "it allows valid json",
+			input:       `{"name": "app", "port": 80, "hosts": ["a.b"]}`,
+			format:      "json",
+		},
+		TestStruct{
+			description: "it allows valid yaml",
+			input:       "name: app\nport: 80\nmode: prod",
+			format:      "yaml",
+		},
+		TestStruct{
+			expected:    "/: missing required property port",
+			description: "it checks required",
+			input:       `{"name": "app"}`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    "/port: expected integer, got string",
+			description: "it checks types",
+			input:       `port = "80"` + "\nname = \"app\"",
+			format:      "toml",
+		},
+		TestStruct{
+			expected:    "/port: 70000 is more than 65535",
+			description: "it checks maximum",
+			input:       "name: app\nport: 70000",
+			format:      "yaml",
+		},
+		TestStruct{
+			expected:    "/: unexpected property other",
+			description: "it checks additionalProperties",
+			input:       `{"name": "app", "port": 80, "other": 1}`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    `/hosts/1: "A" doesn't match`,
+			description: "it follows $ref into items",
+			input:       `{"name": "app", "port": 80, "hosts": ["a", "A"]}`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    "/mode: test is not one of",
+			description: "it checks enum",
+			input:       `{"name": "app", "port": 80, "mode": "test"}`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    "/: matches 2 of oneOf",
+			description: "it checks oneOf",
+			schema:      `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`,
+			input:       `1`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    "/: unsupported keyword items as an array",
+			description: "it errors on tuple items",
+			schema:      `{"items": [{"type": "string"}]}`,
+			input:       `[1]`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    "invalid schema: /properties/a: unsupported keyword format",
+			description: "it errors on keywords it doesn't know",
+			schema:      `{"properties": {"a": {"type": "string", "format": "email"}}}`,
+			input:       `{}`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    "invalid schema: /: unsupported keyword if, then",
+			description: "it lists every keyword it doesn't know",
+			schema:      `{"if": {"type": "object"}, "then": {"required": ["a"]}}`,
+			input:       `{}`,
+			format:      "json",
+		},
+		TestStruct{
+			description: "it ignores annotations",
+			schema:      `{"$schema": "http://json-schema.org/draft-07/schema#", "title": "a", "description": "b", "definitions": {}, "type": "object"}`,
+			input:       `{}`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    "invalid schema",
+			description: "it errors on an invalid schema",
+			schema:      `{`,
+			input:       `{}`,
+			format:      "json",
+		},
+	} {
+		if test.schema == "" {
+			test.schema = schema
+		}
+
+		data, err := Validate([]byte(test.input), test.format)
+		if !assert.Nil(t, err, test.description) {
+			continue
+		}
+
+		err = ValidateSchema(data, []byte(test.schema))
+		if test.expected == "" {
+			assert.Nil(t, err, test.description)
+			continue
+		}
+
+		if assert.Error(t, err, test.description) {
+			assert.Contains(t, err.Error(), test.expected,
+				test.description)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	upstream "text/template"
+	"text/template/parse"
 
 	"github.com/envygeeks/envp/template/helpers"
 	"github.com/sirupsen/logrus"
@@ -21,9 +22,29 @@ import (
 // the template, and stuff for you.
 type Template struct {
 	*upstream.Template
+	Helpers *helpers.Helpers
+
+	use     string
+	main    string
+	escape  string
+	escaped map[*parse.Tree]bool
+	values  map[string]interface{}
+	debug   bool
+}
+
+// Data is the dot of the template, so you
+// can use things like .Host.NumCPU in templates.
+type Data struct {
+	Host *helpers.Host
+	Data map[string]interface{}
+}
 
-	use   string
-	debug bool
+// data gives you the dot for the template
+func (t *Template) data() *Data {
+	return &Data{
+		Host: t.Helpers.Host(),
+		Data: t.values,
+	}
 }
 
 // New creates a new template, and logs it for
@@ -33,9 +54,15 @@ func New() *Template {
 	upstream := upstream.New("envp")
 	template := &Template{
 		Template: upstream,
+		Helpers:  helpers.New(upstream),
+		escaped:  map[*parse.Tree]bool{},
+		values:   map[string]interface{}{},
+	}
+
+	template.Helpers.Parsed = func() {
+		template.escapeTrees(template.main)
 	}
 
-	helpers.New(upstream)
 	return template
 }
 
@@ -72,10 +99,9 @@ func (t *Template) ParseFile(reader Reader) *upstream.Template {
 	return template
 }
 
-// Compile runs exec on the template.
-// Before you hit this stage you should really be
-// running Load(), and Parse() to get ready.
-func (t *Template) Compile() []byte {
+// pick picks the template that you asked to Use, or
+// base.gohtml, root.gohtml, or the first that we have.
+func (t *Template) pick() *upstream.Template {
 	var template *upstream.Template
 
 	if t.use != "" {
@@ -101,9 +127,24 @@ func (t *Template) Compile() []byte {
 		}
 	}
 
+	return template
+}
+
+// Compile runs exec on the template.
+// Before you hit this stage you should really be
+// running Load(), and Parse() to get ready.
+func (t *Template) Compile() []byte {
+	template := t.pick()
+	t.main = template.Name()
+	if t.format(template.Name()) == "html" {
+		return t.compileHTML(template)
+	}
+
+	t.escapeTrees(template.Name())
+
 	buf := &bytes.Buffer{}
 	logrus.Debugf("executing %s", template.Name())
-	if err := template.Execute(buf, ""); err != nil {
+	if err := template.Execute(buf, t.data()); err != nil {
 		logrus.Fatalln(err)
 	}
 
@@ -140,7 +181,7 @@ func writer(file string) *os.File {
 	}
 
 	logrus.Debugf("opening a writer to %s", file)
-	mode, op := 0644, os.O_CREATE|os.O_WRONLY
+	mode, op := 0644, os.O_CREATE|os.O_WRONLY|os.O_TRUNC
 	writer, err := os.OpenFile(file, op, mode)
 	if err != nil {
 		logrus.Fatalln(err)
@@ -212,6 +253,19 @@ func Open(rs []string, w string) ([]Reader, Writer) {
 	return readers(rs), writer(w)
 }
 
+// OpenReaders opens the readers, so that you can
+// open the writer once you're ready to write, that way
+// a render that fails doesn't touch the file.
+func OpenReaders(rs []string) []Reader {
+	return readers(rs)
+}
+
+// OpenWriter opens the writer, it truncates the file
+// so open it after the render (and validation) is done.
+func OpenWriter(w string) Writer {
+	return writer(w)
+}
+
 /**
  */
 func closeWriter(writer Writer) bool {
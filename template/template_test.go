@@ -6,6 +6,7 @@ package template
 
 import (
 	"io"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -109,6 +110,24 @@ func TestExec(t *testing.T) {
 	}
 }
 
+/**
+ */
+func TestExec__host(t *testing.T) {
+	for _, name := range []string{"host", "host.html"} {
+		template := New()
+		template.ParseFile(&TestReader{
+			Reader: strings.NewReader("{{ .Host.OS }}/{{ .Host.Arch }}"),
+			_name:  name,
+		})
+
+		actual := string(template.Compile())
+		assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, actual,
+			"it has .Host")
+	}
+}
+
+/**
+ */
 func TestWrite(t *testing.T) {
 	type TestStruct struct {
 		expected    string
@@ -0,0 +1,171 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/envygeeks/envp/template/helpers"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	lineRegex = regexp.MustCompile(`line (\d+)`)
+
+	// Validators are the formats that we know how to
+	// validate, each one decodes, and returns the data
+	// (for a schema) and the line of the error if any.
+	Validators = map[string]func([]byte) (interface{}, int, error){
+		"json": validateJSON,
+		"yaml": validateYAML,
+		"toml": validateTOML,
+		"xml":  validateXML,
+		"ini":  validateINI,
+	}
+)
+
+// errLine finds "line N" in error messages, for the
+// parsers that only tell you the line in the message.
+func errLine(err error) int {
+	if m := lineRegex.FindStringSubmatch(err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+
+	return 0
+}
+
+func validateJSON(b []byte) (interface{}, int, error) {
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		var offset int64
+		switch e := err.(type) {
+		case *json.SyntaxError:
+			offset = e.Offset
+		case *json.UnmarshalTypeError:
+			offset = e.Offset
+		}
+
+		line := bytes.Count(b[:offset], []byte("\n")) + 1
+		return nil, line, err
+	}
+
+	return out, 0, nil
+}
+
+func validateYAML(b []byte) (interface{}, int, error) {
+	var out interface{}
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		return nil, errLine(err), err
+	}
+
+	return helpers.Normalize(out), 0, nil
+}
+
+func validateTOML(b []byte) (interface{}, int, error) {
+	var out map[string]interface{}
+	if _, err := toml.Decode(string(b), &out); err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, perr.Position.Line, err
+		}
+
+		return nil, errLine(err), err
+	}
+
+	return helpers.Normalize(out), 0, nil
+}
+
+// validateXML tokenizes the document, and makes sure
+// that there's one root element, and no text outside it.
+func validateXML(b []byte) (interface{}, int, error) {
+	depth, roots := 0, 0
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if e, ok := err.(*xml.SyntaxError); ok {
+				return nil, e.Line, err
+			}
+
+			return nil, 0, err
+		}
+
+		line, _ := dec.InputPos()
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+				if roots > 1 {
+					return nil, line, fmt.Errorf("more than one root element <%s>", t.Name.Local)
+				}
+			}
+
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return nil, line, errors.New("text outside of the root element")
+			}
+		}
+	}
+
+	if roots == 0 {
+		return nil, 0, errors.New("no root element")
+	}
+
+	return nil, 0, nil
+}
+
+func validateINI(b []byte) (interface{}, int, error) {
+	out, err := helpers.ParseINI(string(b))
+	if err != nil {
+		return nil, errLine(err), err
+	}
+
+	return out, 0, nil
+}
+
+// snippet gives you the line that broke, so you can
+// find it in the template, logrus wants one line for it.
+func snippet(b []byte, line int) string {
+	lines := strings.Split(string(b), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%d | %s)", line, strings.TrimRight(
+		lines[line-1], "\r"))
+}
+
+// Validate parses what you rendered as the format, so
+// invalid output is never written, it gives you back the
+// decoded data so that you can check it with a schema.
+func Validate(b []byte, format string) (interface{}, error) {
+	validator, ok := Validators[format]
+	if !ok {
+		return nil, fmt.Errorf("unable to validate %s", format)
+	}
+
+	out, line, err := validator(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s%s", format, err,
+			snippet(b, line))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2018 Jordon Bedwell. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE file.
+
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	type TestStruct struct {
+		expected    string
+		description string
+		format      string
+		input       string
+	}
+
+	for _, test := range []TestStruct{
+		TestStruct{
+			description: "it allows valid json",
+			input:       `{"a": [1, 2]}`,
+			format:      "json",
+		},
+		TestStruct{
+			expected:    `(2 | "b": 2})`,
+			description: "it shows the json line",
+			input:       "{\"a\": 1\n\"b\": 2}",
+			format:      "json",
+		},
+		TestStruct{
+			description: "it allows valid yaml",
+			input:       "a:\n  b: 1",
+			format:      "yaml",
+		},
+		TestStruct{
+			expected:    "(3 | b: [)",
+			description: "it shows the yaml line",
+			input:       "a: 1\n\nb: [",
+			format:      "yaml",
+		},
+		TestStruct{
+			description: "it allows valid toml",
+			input:       "a = 1\n[b]\nc = \"d\"",
+			format:      "toml",
+		},
+		TestStruct{
+			description: "it allows toml 1.0 dotted keys",
+			input:       "a.b = 1\nc = 2",
+			format:      "toml",
+		},
+		TestStruct{
+			expected:    "(2 | b = nope)",
+			description: "it shows the toml line",
+			input:       "a = 1\nb = nope\nc = 2",
+			format:      "toml",
+		},
+		TestStruct{
+			description: "it allows valid xml",
+			input:       "<a><b>c</b></a>",
+			format:      "xml",
+		},
+		TestStruct{
+			expected:    "(2 | </b>)",
+			description: "it shows the xml line",
+			input:       "<a>\n</b>",
+			format:      "xml",
+		},
+		TestStruct{
+			description: "it allows a prolog, and comments",
+			input:       "<?xml version=\"1.0\"?>\n<!-- a -->\n<a/>\n",
+			format:      "xml",
+		},
+		TestStruct{
+			expected:    "invalid xml: no root element",
+			description: "it needs a root element",
+			input:       " \n",
+			format:      "xml",
+		},
+		TestStruct{
+			expected:    "more than one root element <b> (2 | <b/>)",
+			description: "it only allows one root element",
+			input:       "<a/>\n<b/>",
+			format:      "xml",
+		},
+		TestStruct{
+			expected:    "text outside of the root element",
+			description: "it doesn't allow text outside the root",
+			input:       "<a/>hello",
+			format:      "xml",
+		},
+		TestStruct{
+			expected:    "text outside of the root element (1 | hello)",
+			description: "it doesn't allow bare text",
+			input:       "hello",
+			format:      "xml",
+		},
+		TestStruct{
+			description: "it allows valid ini",
+			input:       "[a]\nb = c",
+			format:      "ini",
+		},
+		TestStruct{
+			description: "it allows ini with colons, and bare keys",
+			input:       "[a]\nb: c\nd",
+			format:      "ini",
+		},
+		TestStruct{
+			expected:    "(2 | = c)",
+			description: "it shows the ini line",
+			input:       "[a]\n= c",
+			format:      "ini",
+		},
+		TestStruct{
+			expected:    "unable to validate md",
+			description: "it errors on unknown formats",
+			format:      "md",
+		},
+	} {
+		_, err := Validate([]byte(test.input), test.format)
+		if test.expected == "" {
+			assert.Nil(t, err, test.description)
+			continue
+		}
+
+		if assert.Error(t, err, test.description) {
+			assert.Contains(t, err.Error(), test.expected,
+				test.description)
+		}
+	}
+}